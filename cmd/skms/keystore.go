@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"simple-eth-hd-wallet/internal/wallet"
+)
+
+// maxKeystoreSize bounds keystore JSON files
+const maxKeystoreSize = 64 << 10
+
+// keystoreCommand dispatches the keystore subcommands
+func keystoreCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("keystore command requires a subcommand: export or import")
+	}
+
+	switch args[0] {
+	case "export":
+		return exportKeystore(args[1:])
+	case "import":
+		return importKeystore(args[1:])
+	default:
+		return fmt.Errorf("unknown keystore subcommand: %s", args[0])
+	}
+}
+
+// exportKeystore writes the private key of a derived account as a keystore
+// v3 JSON file
+func exportKeystore(args []string) error {
+	flags := flag.NewFlagSet("keystore export", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme")
+	path := flags.String("path", "", "export the key at this path instead of an index")
+	kdf := flags.String("kdf", wallet.KDFScrypt, "key derivation function: scrypt or pbkdf2")
+	light := flags.Bool("light", false, "use light scrypt parameters (N=4096, p=6)")
+	out := flags.String("out", "", "write the keystore to this new file instead of stdout")
+	passwordFile := flags.String("password-file", "", "read the keystore password from a file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	switch {
+	case *path == "" && len(args) < 2:
+		return fmt.Errorf("keystore export command requires mnemonic phrase and account index")
+	case *path != "" && len(args) != 1:
+		return fmt.Errorf("keystore export command with --path requires a mnemonic phrase only")
+	}
+
+	var params wallet.KeystoreParams
+	switch {
+	case *kdf == wallet.KDFPBKDF2 && !*light:
+		params = wallet.PBKDF2KeystoreParams
+	case *kdf == wallet.KDFScrypt && *light:
+		params = wallet.LightKeystoreParams
+	case *kdf == wallet.KDFScrypt:
+		params = wallet.StandardKeystoreParams
+	default:
+		return fmt.Errorf("unsupported keystore options: --kdf %s --light=%t", *kdf, *light)
+	}
+
+	var w *wallet.SimpleWallet
+	var account *wallet.Account
+	var err error
+	if *path != "" {
+		w, account, err = openAccountAtPath(args[0], *path)
+	} else {
+		w, account, err = openAccount(args[0], args[1], *scheme)
+	}
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	password, err := readPassphrase(*passwordFile, "Keystore password", true)
+	if err != nil {
+		return err
+	}
+	data, err := w.ExportKeystore(account.Address, password, params)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt keystore: %v", err)
+	}
+
+	if *out == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := writeNewFile(*out, append(data, '\n')); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Keystore for %s (%s) written to %s\n", account.Address.Hex(), account.Path, *out)
+	return nil
+}
+
+// importKeystore decrypts a keystore v3 JSON file and, given a mnemonic,
+// finds the derived account it holds
+func importKeystore(args []string) error {
+	flags := flag.NewFlagSet("keystore import", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme to search")
+	path := flags.String("path", "", "match the key at this path instead of searching indexes")
+	maxIndex := flags.Uint("max-index", 100, "number of account indexes to search")
+	passwordFile := flags.String("password-file", "", "read the keystore password from a file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("keystore import command requires a keystore file and an optional mnemonic phrase")
+	}
+
+	data, err := readInputLimit(args[0], maxKeystoreSize)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore: %v", err)
+	}
+	password, err := readPassphrase(*passwordFile, "Keystore password", false)
+	if err != nil {
+		return err
+	}
+	key, err := wallet.DecryptKeystore(data, password)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+	address := wallet.PubkeyToAddress(&key.PublicKey)
+
+	fmt.Printf("✅ Keystore decrypted\n\n")
+	fmt.Printf("Ethereum Address: %s\n", address.Hex())
+
+	if len(args) < 2 {
+		fmt.Printf("\nPass the mnemonic phrase to find the derived account this key belongs to\n")
+		return nil
+	}
+
+	account, err := findDerivedAccount(args[1], address, *path, *scheme, uint32(*maxIndex))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Derivation Path:  %s\n", account.Path)
+	if *path == "" {
+		fmt.Printf("Account Index:    %d (%s)\n", account.Index, *scheme)
+	}
+	return nil
+}
+
+// findDerivedAccount derives the account at path, or searches the first
+// maxIndex indexes of scheme, for the account with the given address
+func findDerivedAccount(mnemonic string, address wallet.Address, path, scheme string, maxIndex uint32) (*wallet.Account, error) {
+	config := wallet.DefaultConfig()
+	config.Scheme = scheme
+	w, err := wallet.NewFromMnemonic(mnemonic, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %v", err)
+	}
+	defer w.Close()
+
+	if path != "" {
+		account, err := w.DeriveAtPathString(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account: %v", err)
+		}
+		if account.Address != address {
+			return nil, fmt.Errorf("keystore key is not the account at %s (%s)", path, account.Address.Hex())
+		}
+		return account, nil
+	}
+
+	for index := uint32(0); index < maxIndex; index++ {
+		account, err := w.Derive(index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive account: %v", err)
+		}
+		if account.Address == address {
+			return account, nil
+		}
+	}
+	return nil, fmt.Errorf("keystore key is not among the first %d %s accounts of the mnemonic", maxIndex, scheme)
+}
+
+// writeNewFile writes data to a file that must not already exist, readable
+// only by the owner
+func writeNewFile(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
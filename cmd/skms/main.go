@@ -5,9 +5,13 @@
 package main
 
 import (
+	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"simple-eth-hd-wallet/internal/wallet"
 )
@@ -15,8 +19,15 @@ import (
 const (
 	version = "1.0.0"
 	appName = "SKMS - Secure Key Management System"
+
+	// passphraseEnv names the environment variable read for passphrases
+	// when no --password-file is given
+	passphraseEnv = "SKMS_PASSPHRASE"
 )
 
+// errNotTerminal is returned by disableEcho for input that is not a terminal
+var errNotTerminal = errors.New("not a terminal")
+
 // printUsage displays the CLI usage information
 func printUsage() {
 	fmt.Printf(`%s v%s
@@ -28,9 +39,54 @@ Commands:
   generate [entropy-bits]    Generate a new BIP-39 mnemonic phrase
                             entropy-bits: 128, 160, 192, 224, or 256 (default: 128)
   
-  derive <mnemonic> <index>  Derive an Ethereum account from mnemonic
+  derive [--scheme name] <mnemonic> <index>
+                            Derive an Ethereum account from mnemonic
                             mnemonic: BIP-39 mnemonic phrase (quoted)
                             index: account index (0, 1, 2, ...)
+                            --scheme: derivation layout (default: bip44)
+%s  
+  sign-message [--scheme name] [--file path] <mnemonic> <index>
+                            Sign a message (EIP-191 personal_sign) read from
+                            stdin or --file and print the signature
+  
+  verify-message [--file path] <address> <signature>
+                            Check that a message read from stdin or --file
+                            was signed by address
+  
+  sign-typed-data [--scheme name] <file> <mnemonic> <index>
+                            Sign an EIP-712 typed data JSON document
+                            (eth_signTypedData_v4); file "-" reads stdin
+  
+  tx sign [--scheme name | --path path] [--json] <file> <mnemonic> [index]
+                            Sign an unsigned transaction JSON (legacy or
+                            type 1-4, with chainId) for offline broadcast;
+                            file "-" reads stdin
+  
+  init [--scheme name] [--label name] [--entropy bits] [--password-file file]
+       <vault-file> [mnemonic]
+                            Create an encrypted wallet vault from a new
+                            mnemonic (printed once for backup) or a given one
+  
+  open [--derive index | --path path] [--label name] [--password-file file]
+       <vault-file>
+                            List the accounts of a wallet vault, optionally
+                            deriving and labelling a new one
+  
+  wallets list [--dir dir]   List the wallet vaults in a directory by ID and
+                            label without asking for passphrases
+  
+  wallets find [--dir dir] <address>
+                            Find the wallet vaults holding an address
+  
+  keystore export [--scheme name | --path path] [--kdf scrypt|pbkdf2] [--light]
+                  [--out file] [--password-file file] <mnemonic> [index]
+                            Encrypt a derived account's private key as an
+                            Ethereum keystore v3 (geth, MetaMask) JSON file
+  
+  keystore import [--scheme name | --path path] [--max-index n]
+                  [--password-file file] <file> [mnemonic]
+                            Decrypt a keystore v3 file and find the derived
+                            account of the mnemonic that it holds
   
   help                      Show this help message
   version                   Show version information
@@ -38,6 +94,20 @@ Commands:
 Examples:
   skms generate 128
   skms derive "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about" 0
+  skms derive --scheme ledger-live "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about" 1
+  printf 'I own this address' | skms sign-message "<mnemonic>" 0
+  printf 'I own this address' | skms verify-message 0x9858EfFD232B4033E47d90003D41EC34EcaEda94 0x<signature>
+  skms sign-typed-data permit.json "<mnemonic>" 0
+  skms tx sign transfer.json "<mnemonic>" 0
+  skms tx sign --json --path "m/44'/60'/0'/0/7" - "<mnemonic>" < transfer.json
+  skms init --label treasury treasury.json
+  skms open --derive 1 --label payroll treasury.json
+  skms wallets list --dir ~/.skms/wallets
+  skms keystore export --out account0.json "<mnemonic>" 0
+  skms keystore import --password-file pw.txt account0.json "<mnemonic>"
+
+Passphrases are read from --password-file, the %s environment
+variable, or prompted for on the terminal without echoing the input.
 
 Security Warning:
   This tool handles sensitive cryptographic material. Always:
@@ -46,7 +116,16 @@ Security Warning:
   • Verify the integrity of generated keys
   • Use hardware wallets for production funds
 
-`, appName, version)
+`, appName, version, schemeUsage(), passphraseEnv)
+}
+
+// schemeUsage lists the registered derivation schemes for the help text
+func schemeUsage() string {
+	var b strings.Builder
+	for _, scheme := range wallet.DerivationSchemes() {
+		fmt.Fprintf(&b, "                              %-14s %s\n", scheme.Name, scheme.Template)
+	}
+	return b.String()
 }
 
 // printVersion displays version information
@@ -91,38 +170,29 @@ func generateMnemonic(args []string) error {
 
 // deriveAccount handles account derivation
 func deriveAccount(args []string) error {
-	if len(args) < 2 {
-		return fmt.Errorf("derive command requires mnemonic phrase and account index")
+	flags := flag.NewFlagSet("derive", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme")
+	if err := flags.Parse(args); err != nil {
+		return err
 	}
+	args = flags.Args()
 
-	mnemonic := args[0]
-	indexStr := args[1]
-
-	// Parse account index
-	index, err := strconv.ParseUint(indexStr, 10, 32)
-	if err != nil {
-		return fmt.Errorf("invalid account index: %v", err)
+	if len(args) < 2 {
+		return fmt.Errorf("derive command requires mnemonic phrase and account index")
 	}
 
-	fmt.Printf("Deriving account at index %d...\n", index)
-
-	// Create wallet from mnemonic
-	config := wallet.DefaultConfig()
-	w, err := wallet.NewFromMnemonic(mnemonic, config)
+	w, account, err := openAccount(args[0], args[1], *scheme)
 	if err != nil {
-		return fmt.Errorf("failed to create wallet: %v", err)
+		return err
 	}
 	defer w.Close()
 
-	// Derive the account
-	account, err := w.Derive(uint32(index))
-	if err != nil {
-		return fmt.Errorf("failed to derive account: %v", err)
-	}
+	fmt.Printf("Deriving account at index %d...\n", account.Index)
 
 	// Display account information
 	fmt.Printf("\n✅ Account derived successfully!\n\n")
 	fmt.Printf("Account Index:    %d\n", account.Index)
+	fmt.Printf("Scheme:           %s\n", w.Scheme().Name)
 	fmt.Printf("Derivation Path:  %s\n", account.Path)
 	fmt.Printf("Ethereum Address: %s\n", account.Address.String())
 
@@ -145,6 +215,99 @@ func deriveAccount(args []string) error {
 	return nil
 }
 
+// openAccount creates a wallet from mnemonic with the named derivation scheme
+// and derives the account at the decimal index
+func openAccount(mnemonic, indexStr, scheme string) (*wallet.SimpleWallet, *wallet.Account, error) {
+	// Parse account index
+	index, err := strconv.ParseUint(indexStr, 10, 32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid account index: %v", err)
+	}
+
+	// Create wallet from mnemonic
+	config := wallet.DefaultConfig()
+	config.Scheme = scheme
+	w, err := wallet.NewFromMnemonic(mnemonic, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create wallet: %v", err)
+	}
+
+	// Derive the account
+	account, err := w.Derive(uint32(index))
+	if err != nil {
+		w.Close()
+		return nil, nil, fmt.Errorf("failed to derive account: %v", err)
+	}
+
+	return w, account, nil
+}
+
+// openAccountAtPath creates a wallet from mnemonic and derives the account at
+// an explicit BIP-32 path such as m/44'/60'/0'/0/0
+func openAccountAtPath(mnemonic, path string) (*wallet.SimpleWallet, *wallet.Account, error) {
+	w, err := wallet.NewFromMnemonic(mnemonic, wallet.DefaultConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create wallet: %v", err)
+	}
+
+	account, err := w.DeriveAtPathString(path)
+	if err != nil {
+		w.Close()
+		return nil, nil, fmt.Errorf("failed to derive account: %v", err)
+	}
+
+	return w, account, nil
+}
+
+// readPassphrase reads a passphrase from file, the SKMS_PASSPHRASE
+// environment variable or, failing both, a prompt on stdin with terminal echo
+// turned off. With confirm the prompt asks twice and the answers must match.
+func readPassphrase(file, prompt string, confirm bool) (string, error) {
+	if file != "" {
+		data, err := readInputLimit(file, 1024)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	if passphrase, ok := os.LookupEnv(passphraseEnv); ok {
+		return passphrase, nil
+	}
+
+	restore, err := disableEcho(os.Stdin)
+	if err != nil && err != errNotTerminal {
+		return "", err
+	}
+	if restore != nil {
+		defer restore()
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	ask := func(prompt string) (string, error) {
+		fmt.Fprintf(os.Stderr, "%s: ", prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read passphrase: %v", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	passphrase, err := ask(prompt)
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := ask("Repeat " + strings.ToLower(prompt[:1]) + prompt[1:])
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
 // main is the application entry point
 func main() {
 	if len(os.Args) < 2 {
@@ -162,6 +325,22 @@ func main() {
 		err = generateMnemonic(args)
 	case "derive":
 		err = deriveAccount(args)
+	case "sign-message":
+		err = signMessage(args)
+	case "verify-message":
+		err = verifyMessage(args)
+	case "sign-typed-data":
+		err = signTypedData(args)
+	case "tx":
+		err = txCommand(args)
+	case "init":
+		err = initVault(args)
+	case "open":
+		err = openVault(args)
+	case "wallets":
+		err = walletsCommand(args)
+	case "keystore":
+		err = keystoreCommand(args)
 	case "help", "--help", "-h":
 		printUsage()
 		return
@@ -0,0 +1,9 @@
+package main
+
+import "syscall"
+
+// Terminal attribute ioctl requests
+const (
+	ioctlReadTermios  = syscall.TCGETS
+	ioctlWriteTermios = syscall.TCSETS
+)
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// disableEcho cannot turn off terminal echo on this platform, so it refuses
+// to prompt on a terminal rather than display the passphrase. Input from a
+// pipe or file is read as is.
+func disableEcho(f *os.File) (func(), error) {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, errNotTerminal
+	}
+	return nil, fmt.Errorf("cannot hide passphrase input on this platform; use --password-file or %s", passphraseEnv)
+}
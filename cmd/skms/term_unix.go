@@ -0,0 +1,36 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// disableEcho turns off echo on the terminal f so that a passphrase typed at
+// a prompt is not displayed. It returns a function restoring the previous
+// mode, or errNotTerminal if f is not a terminal.
+func disableEcho(f *os.File) (func(), error) {
+	fd := f.Fd()
+	var saved syscall.Termios
+	if err := termios(fd, ioctlReadTermios, &saved); err != nil {
+		return nil, errNotTerminal
+	}
+
+	noEcho := saved
+	noEcho.Lflag &^= syscall.ECHO
+	noEcho.Lflag |= syscall.ICANON | syscall.ECHONL
+	if err := termios(fd, ioctlWriteTermios, &noEcho); err != nil {
+		return nil, err
+	}
+	return func() { termios(fd, ioctlWriteTermios, &saved) }, nil
+}
+
+// termios gets or sets the terminal attributes of fd
+func termios(fd, request uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
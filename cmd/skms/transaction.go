@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"simple-eth-hd-wallet/internal/wallet"
+)
+
+// maxTxInputSize bounds transaction JSON, leaving room for blob sidecars
+const maxTxInputSize = 16 << 20
+
+// txTypeNames describes each supported transaction type
+var txTypeNames = map[uint8]string{
+	wallet.LegacyTxType:     "legacy",
+	wallet.AccessListTxType: "EIP-2930 access list",
+	wallet.DynamicFeeTxType: "EIP-1559 dynamic fee",
+	wallet.BlobTxType:       "EIP-4844 blob",
+	wallet.SetCodeTxType:    "EIP-7702 set code",
+}
+
+// signedTx is the --json output of tx sign
+type signedTx struct {
+	From           wallet.Address      `json:"from"`
+	Path           string              `json:"path"`
+	Hash           string              `json:"hash"`
+	RawTransaction string              `json:"rawTransaction"`
+	Transaction    *wallet.Transaction `json:"transaction"`
+}
+
+// txCommand dispatches the tx subcommands
+func txCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("tx command requires a subcommand: sign")
+	}
+
+	switch args[0] {
+	case "sign":
+		return signTransaction(args[1:])
+	default:
+		return fmt.Errorf("unknown tx subcommand: %s", args[0])
+	}
+}
+
+// signTransaction handles offline signing of a transaction read as JSON
+func signTransaction(args []string) error {
+	flags := flag.NewFlagSet("tx sign", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme")
+	path := flags.String("path", "", "derive the signing key at this path instead of an index")
+	jsonOutput := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	switch {
+	case *path == "" && len(args) < 3:
+		return fmt.Errorf("tx sign command requires a JSON file, mnemonic phrase and account index")
+	case *path != "" && len(args) != 2:
+		return fmt.Errorf("tx sign command with --path requires a JSON file and mnemonic phrase only")
+	}
+
+	data, err := readInputLimit(args[0], maxTxInputSize)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction: %v", err)
+	}
+	tx := new(wallet.Transaction)
+	if err := json.Unmarshal(data, tx); err != nil {
+		return err
+	}
+	if tx.ChainID == nil {
+		return fmt.Errorf("transaction has no chainId")
+	}
+	if tx.IsSigned() {
+		return fmt.Errorf("transaction is already signed")
+	}
+
+	var from struct {
+		From *wallet.Address `json:"from"`
+	}
+	if err := json.Unmarshal(data, &from); err != nil {
+		return err
+	}
+
+	var w *wallet.SimpleWallet
+	var account *wallet.Account
+	if *path != "" {
+		w, account, err = openAccountAtPath(args[1], *path)
+	} else {
+		w, account, err = openAccount(args[1], args[2], *scheme)
+	}
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if from.From != nil && *from.From != account.Address {
+		return fmt.Errorf("transaction is from %s but the key derives %s", from.From.Hex(), account.Address.Hex())
+	}
+
+	signed, err := w.SignTransaction(account.Address, tx)
+	if err != nil {
+		return err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	hash, err := signed.Hash()
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		out, err := json.MarshalIndent(signedTx{
+			From:           account.Address,
+			Path:           account.Path,
+			Hash:           "0x" + hex.EncodeToString(hash),
+			RawTransaction: "0x" + hex.EncodeToString(raw),
+			Transaction:    signed,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	printTxSummary(account, signed)
+	fmt.Printf("\nRaw Transaction:  0x%s\n", hex.EncodeToString(raw))
+	fmt.Printf("Transaction Hash: 0x%s\n", hex.EncodeToString(hash))
+	if signed.Type == wallet.BlobTxType && signed.Sidecar == nil {
+		fmt.Printf("\n⚠️  No blob sidecar: the raw transaction is in canonical form and cannot be broadcast as is\n")
+	}
+
+	return nil
+}
+
+// printTxSummary prints the fields of a transaction for review before it is
+// broadcast
+func printTxSummary(account *wallet.Account, tx *wallet.Transaction) {
+	fmt.Printf("Type:             %d (%s)\n", tx.Type, txTypeNames[tx.Type])
+	fmt.Printf("From:             %s\n", account.Address.Hex())
+	fmt.Printf("Path:             %s\n", account.Path)
+	fmt.Printf("Chain ID:         %s\n", tx.ChainID)
+	fmt.Printf("Nonce:            %d\n", tx.Nonce)
+	if tx.To != nil {
+		fmt.Printf("To:               %s\n", tx.To.Hex())
+	} else {
+		fmt.Printf("To:               (contract creation)\n")
+	}
+	fmt.Printf("Value:            %s ETH\n", formatUnits(tx.Value, 18))
+	fmt.Printf("Gas Limit:        %d\n", tx.Gas)
+
+	switch tx.Type {
+	case wallet.LegacyTxType, wallet.AccessListTxType:
+		fmt.Printf("Gas Price:        %s gwei\n", formatUnits(tx.GasPrice, 9))
+	default:
+		fmt.Printf("Max Fee:          %s gwei\n", formatUnits(tx.GasFeeCap, 9))
+		fmt.Printf("Max Priority Fee: %s gwei\n", formatUnits(tx.GasTipCap, 9))
+	}
+
+	fmt.Printf("Data:             %d bytes\n", len(tx.Data))
+	if tx.AccessList != nil {
+		keys := 0
+		for _, tuple := range tx.AccessList {
+			keys += len(tuple.StorageKeys)
+		}
+		fmt.Printf("Access List:      %d addresses, %d storage keys\n", len(tx.AccessList), keys)
+	}
+
+	if tx.Type == wallet.BlobTxType {
+		fmt.Printf("Max Blob Fee:     %s gwei\n", formatUnits(tx.BlobFeeCap, 9))
+		fmt.Printf("Blobs:            %d\n", len(tx.BlobHashes))
+		for _, hash := range tx.BlobHashes {
+			fmt.Printf("  0x%s\n", hex.EncodeToString(hash[:]))
+		}
+	}
+
+	if tx.Type == wallet.SetCodeTxType {
+		fmt.Printf("Authorizations:   %d\n", len(tx.AuthList))
+		for _, auth := range tx.AuthList {
+			authority := "unsigned"
+			if signer, err := auth.Authority(); err == nil {
+				authority = signer.Hex()
+			}
+			fmt.Printf("  %s delegates to %s (chain %s, nonce %d)\n", authority, auth.Address.Hex(), auth.ChainID, auth.Nonce)
+		}
+	}
+}
+
+// formatUnits formats an integer amount with the given number of decimals,
+// such as wei as ETH, without trailing zeros
+func formatUnits(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	digits := amount.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return whole
+	}
+	return whole + "." + fraction
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+
+	"simple-eth-hd-wallet/internal/wallet"
+)
+
+// signTypedData handles EIP-712 eth_signTypedData_v4 signing
+func signTypedData(args []string) error {
+	flags := flag.NewFlagSet("sign-typed-data", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) < 3 {
+		return fmt.Errorf("sign-typed-data command requires a JSON file, mnemonic phrase and account index")
+	}
+
+	data, err := readInput(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read typed data: %v", err)
+	}
+	td, err := wallet.ParseTypedData(data)
+	if err != nil {
+		return err
+	}
+
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return err
+	}
+	hash, err := td.SigningHash()
+	if err != nil {
+		return err
+	}
+
+	w, account, err := openAccount(args[1], args[2], *scheme)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	sig, err := w.SignTypedData(account.Address, td)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Address:          %s\n", account.Address.Hex())
+	fmt.Printf("Path:             %s\n", account.Path)
+	fmt.Printf("Primary Type:     %s\n", td.PrimaryType)
+	fmt.Printf("Domain Separator: 0x%s\n", hex.EncodeToString(domainSeparator))
+	fmt.Printf("Signing Hash:     0x%s\n", hex.EncodeToString(hash))
+	fmt.Printf("Signature:        0x%s\n", hex.EncodeToString(sig))
+
+	return nil
+}
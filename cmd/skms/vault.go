@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"simple-eth-hd-wallet/internal/wallet"
+)
+
+// initVault creates an encrypted wallet vault from a new or given mnemonic
+func initVault(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	scheme := flags.String("scheme", wallet.SchemeBIP44, "derivation scheme")
+	label := flags.String("label", "", "wallet label")
+	entropyBits := flags.Int("entropy", 128, "entropy bits of a generated mnemonic")
+	passwordFile := flags.String("password-file", "", "read the vault passphrase from a file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("init command requires a vault file and an optional mnemonic phrase")
+	}
+	path := args[0]
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	mnemonic, generated := "", len(args) < 2
+	if generated {
+		var err error
+		mnemonic, err = wallet.GenerateMnemonic(*entropyBits)
+		if err != nil {
+			return fmt.Errorf("failed to generate mnemonic: %v", err)
+		}
+	} else {
+		mnemonic = args[1]
+	}
+
+	config := wallet.DefaultConfig()
+	config.Scheme = *scheme
+	w, err := wallet.NewFromMnemonic(mnemonic, config)
+	if err != nil {
+		return fmt.Errorf("failed to create wallet: %v", err)
+	}
+	defer w.Close()
+	w.SetLabel(*label)
+
+	account, err := w.Derive(0)
+	if err != nil {
+		return fmt.Errorf("failed to derive account: %v", err)
+	}
+
+	passphrase, err := readPassphrase(*passwordFile, "Vault passphrase", true)
+	if err != nil {
+		return err
+	}
+	// SaveNew also refuses a file created while the vault was encrypted
+	if err := w.SaveNew(path, passphrase); errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists", path)
+	} else if err != nil {
+		return fmt.Errorf("failed to save vault: %v", err)
+	}
+
+	fmt.Printf("✅ Wallet vault created at %s\n\n", path)
+	fmt.Printf("Wallet ID:        %s\n", w.ID())
+	fmt.Printf("Scheme:           %s\n", w.Scheme().Name)
+	fmt.Printf("Account 0:        %s\n", account.Address.Hex())
+	if generated {
+		fmt.Printf("\nMnemonic Phrase:\n%s\n\n", mnemonic)
+		fmt.Printf("⚠️  Write down this mnemonic phrase: it recovers the wallet if the vault\n")
+		fmt.Printf("file or its passphrase is lost\n")
+	}
+	return nil
+}
+
+// openVault opens a wallet vault, optionally derives and labels a new
+// account, and lists the wallet's accounts
+func openVault(args []string) error {
+	flags := flag.NewFlagSet("open", flag.ContinueOnError)
+	derive := flags.Int("derive", -1, "derive the account at this index and save it")
+	path := flags.String("path", "", "derive the account at this path and save it")
+	label := flags.String("label", "", "label for the account derived with --derive or --path")
+	passwordFile := flags.String("password-file", "", "read the vault passphrase from a file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		return fmt.Errorf("open command requires a vault file")
+	}
+	// Check the range here: converting a larger index to uint32 would wrap
+	// and silently derive a different account
+	if *derive < -1 || int64(*derive) >= wallet.HardenedKeyStart {
+		return fmt.Errorf("--derive index must be between 0 and %d", wallet.HardenedKeyStart-1)
+	}
+	if *derive >= 0 && *path != "" {
+		return fmt.Errorf("--derive and --path cannot be combined")
+	}
+	if *label != "" && *derive < 0 && *path == "" {
+		return fmt.Errorf("--label requires --derive or --path")
+	}
+
+	passphrase, err := readPassphrase(*passwordFile, "Vault passphrase", false)
+	if err != nil {
+		return err
+	}
+	w, err := wallet.OpenWallet(args[0], passphrase, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open vault: %v", err)
+	}
+	defer w.Close()
+
+	if *derive >= 0 || *path != "" {
+		var account *wallet.Account
+		if *path != "" {
+			account, err = w.DeriveAtPathString(*path)
+		} else {
+			account, err = w.Derive(uint32(*derive))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to derive account: %v", err)
+		}
+		if *label != "" {
+			if err := w.SetAccountLabel(account.Address, *label); err != nil {
+				return err
+			}
+		}
+		if err := w.Save(args[0], passphrase); err != nil {
+			return fmt.Errorf("failed to save vault: %v", err)
+		}
+		fmt.Printf("✅ Account %s saved to %s\n\n", account.Address.Hex(), args[0])
+	}
+
+	fmt.Printf("Wallet ID:        %s\n", w.ID())
+	if w.Label() != "" {
+		fmt.Printf("Label:            %s\n", w.Label())
+	}
+	fmt.Printf("Scheme:           %s\n", w.Scheme().Name)
+	fmt.Printf("\nAccounts:\n")
+	for _, account := range sortedAccounts(w) {
+		fmt.Printf("  %-44s %-20s %s\n", account.Address.Hex(), account.Path, account.Label)
+	}
+	return nil
+}
+
+// sortedAccounts returns the accounts of w in the order they were derived
+func sortedAccounts(w *wallet.SimpleWallet) []*wallet.Account {
+	accounts := w.Accounts()
+	sort.Slice(accounts, func(i, j int) bool {
+		if !accounts[i].CreatedAt.Equal(accounts[j].CreatedAt) {
+			return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+		}
+		return accounts[i].Path < accounts[j].Path
+	})
+	return accounts
+}
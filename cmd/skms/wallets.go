@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"simple-eth-hd-wallet/internal/wallet"
+)
+
+// walletsCommand dispatches the wallets subcommands
+func walletsCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("wallets command requires a subcommand: list or find")
+	}
+
+	switch args[0] {
+	case "list":
+		return listWallets(args[1:])
+	case "find":
+		return findWallet(args[1:])
+	default:
+		return fmt.Errorf("unknown wallets subcommand: %s", args[0])
+	}
+}
+
+// listWallets lists the wallet vaults in a directory from their headers,
+// without asking for any passphrase
+func listWallets(args []string) error {
+	flags := flag.NewFlagSet("wallets list", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of wallet vault files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	m, err := openManager(*dir)
+	if err != nil {
+		return err
+	}
+
+	wallets := m.List()
+	if len(wallets) == 0 {
+		fmt.Printf("No wallet vaults in %s\n", *dir)
+		return nil
+	}
+	fmt.Printf("%-36s  %-20s  %-8s  %s\n", "ID", "LABEL", "ACCOUNTS", "FILE")
+	for _, info := range wallets {
+		fmt.Printf("%-36s  %-20s  %-8d  %s\n", info.ID, info.Label, len(info.Addresses), info.Path)
+	}
+	return nil
+}
+
+// findWallet lists the wallet vaults in a directory that hold an address
+func findWallet(args []string) error {
+	flags := flag.NewFlagSet("wallets find", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of wallet vault files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		return fmt.Errorf("wallets find command requires an address")
+	}
+	address, err := wallet.ParseAddress(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid address: %v", err)
+	}
+
+	m, err := openManager(*dir)
+	if err != nil {
+		return err
+	}
+	found := m.FindAddress(address)
+	if len(found) == 0 {
+		return fmt.Errorf("no wallet in %s holds %s", *dir, address.Hex())
+	}
+	for _, info := range found {
+		fmt.Printf("%s  %-20s  %s\n", info.ID, info.Label, info.Path)
+	}
+	return nil
+}
+
+// openManager indexes the wallet vaults in dir and warns about the files that
+// were skipped
+func openManager(dir string) (*wallet.Manager, error) {
+	m, err := wallet.NewManager(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, problem := range m.Problems() {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %v\n", problem)
+	}
+	return m, nil
+}
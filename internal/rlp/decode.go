@@ -0,0 +1,246 @@
+package rlp
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// DecodeBytes parses the RLP item in b into the value pointed to by val.
+// The input must hold exactly one item in canonical form.
+func DecodeBytes(b []byte, val interface{}) error {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("rlp: DecodeBytes requires a non-nil pointer, got %T", val)
+	}
+
+	rest, err := decodeValue(b, v.Elem())
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return ErrMoreThanOneValue
+	}
+	return nil
+}
+
+// decodeValue decodes the first item of b into v and returns the rest
+func decodeValue(b []byte, v reflect.Value) ([]byte, error) {
+	typ := v.Type()
+
+	switch {
+	case typ == rawValueType:
+		_, _, rest, err := Split(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetBytes(append([]byte(nil), b[:len(b)-len(rest)]...))
+		return rest, nil
+	case typ == bigIntType:
+		i := v.Addr().Interface().(*big.Int)
+		return decodeBigInt(b, i)
+	case typ.Kind() == reflect.Ptr && typ.Elem() == bigIntType:
+		i := new(big.Int)
+		rest, err := decodeBigInt(b, i)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(reflect.ValueOf(i))
+		return rest, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		content, rest, err := SplitString(b)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(content) == 0:
+			v.SetBool(false)
+		case len(content) == 1 && content[0] == 1:
+			v.SetBool(true)
+		default:
+			return nil, fmt.Errorf("rlp: invalid boolean value %x", content)
+		}
+		return rest, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		content, rest, err := SplitString(b)
+		if err != nil {
+			return nil, err
+		}
+		i, err := decodeUint(content, int(typ.Size()))
+		if err != nil {
+			return nil, err
+		}
+		v.SetUint(i)
+		return rest, nil
+
+	case reflect.String:
+		content, rest, err := SplitString(b)
+		if err != nil {
+			return nil, err
+		}
+		v.SetString(string(content))
+		return rest, nil
+
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			content, rest, err := SplitString(b)
+			if err != nil {
+				return nil, err
+			}
+			v.SetBytes(append([]byte{}, content...))
+			return rest, nil
+		}
+		return decodeList(b, v)
+
+	case reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			content, rest, err := SplitString(b)
+			if err != nil {
+				return nil, err
+			}
+			if len(content) != v.Len() {
+				return nil, fmt.Errorf("rlp: input string of %d bytes for %v", len(content), typ)
+			}
+			reflect.Copy(v, reflect.ValueOf(content))
+			return rest, nil
+		}
+		return decodeList(b, v)
+
+	case reflect.Struct:
+		return decodeStruct(b, v)
+
+	case reflect.Ptr:
+		elem := reflect.New(typ.Elem())
+		rest, err := decodeValue(b, elem.Elem())
+		if err != nil {
+			return nil, err
+		}
+		v.Set(elem)
+		return rest, nil
+
+	case reflect.Interface:
+		if typ.NumMethod() != 0 {
+			break
+		}
+		decoded, rest, err := decodeInterface(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(reflect.ValueOf(decoded))
+		return rest, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, typ)
+}
+
+// decodeUint parses a canonical big-endian integer of at most size bytes
+func decodeUint(content []byte, size int) (uint64, error) {
+	if len(content) > size {
+		return 0, ErrUintOverflow
+	}
+	if len(content) > 0 && content[0] == 0 {
+		return 0, ErrCanonInt
+	}
+
+	var i uint64
+	for _, c := range content {
+		i = i<<8 | uint64(c)
+	}
+	return i, nil
+}
+
+// decodeBigInt decodes a canonical non-negative integer into i
+func decodeBigInt(b []byte, i *big.Int) ([]byte, error) {
+	content, rest, err := SplitString(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 && content[0] == 0 {
+		return nil, ErrCanonInt
+	}
+	i.SetBytes(content)
+	return rest, nil
+}
+
+// decodeList decodes a list into a slice, or into an array of exactly
+// matching length
+func decodeList(b []byte, v reflect.Value) ([]byte, error) {
+	content, rest, err := SplitList(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Kind() == reflect.Slice {
+		count, err := CountValues(content)
+		if err != nil {
+			return nil, err
+		}
+		v.Set(reflect.MakeSlice(v.Type(), count, count))
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if len(content) == 0 {
+			return nil, fmt.Errorf("%w for %v", ErrTooFewElements, v.Type())
+		}
+		if content, err = decodeValue(content, v.Index(i)); err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	if len(content) > 0 {
+		return nil, fmt.Errorf("%w for %v", ErrTooManyElements, v.Type())
+	}
+	return rest, nil
+}
+
+// decodeStruct decodes a list into the encodable fields of a struct
+func decodeStruct(b []byte, v reflect.Value) ([]byte, error) {
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	content, rest, err := SplitList(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range fields {
+		name := v.Type().Field(index).Name
+		if len(content) == 0 {
+			return nil, fmt.Errorf("%w for %v: missing %s", ErrTooFewElements, v.Type(), name)
+		}
+		if content, err = decodeValue(content, v.Field(index)); err != nil {
+			return nil, fmt.Errorf("%v.%s: %w", v.Type(), name, err)
+		}
+	}
+	if len(content) > 0 {
+		return nil, fmt.Errorf("%w for %v", ErrTooManyElements, v.Type())
+	}
+	return rest, nil
+}
+
+// decodeInterface decodes an item into []byte for strings or []interface{}
+// for lists
+func decodeInterface(b []byte) (interface{}, []byte, error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != List {
+		return append([]byte{}, content...), rest, nil
+	}
+
+	items := []interface{}{}
+	for len(content) > 0 {
+		var item interface{}
+		if item, content, err = decodeInterface(content); err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rest, nil
+}
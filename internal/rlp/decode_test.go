@@ -0,0 +1,246 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestDecodeVectorsRoundTrip(t *testing.T) {
+	for _, tt := range encodeVectors {
+		t.Run(tt.name, func(t *testing.T) {
+			input := unhex(tt.want)
+
+			// Every vector decodes generically and re-encodes identically
+			var generic interface{}
+			if err := DecodeBytes(input, &generic); err != nil {
+				t.Fatalf("DecodeBytes(interface) failed: %v", err)
+			}
+			again, err := EncodeToBytes(generic)
+			if err != nil || !bytes.Equal(again, input) {
+				t.Errorf("Re-encoding = %x, %v; want %s", again, err, tt.want)
+			}
+
+			// And into a value of the original type
+			target := reflect.New(reflect.TypeOf(tt.value))
+			if err := DecodeBytes(input, target.Interface()); err != nil {
+				t.Fatalf("DecodeBytes(%T) failed: %v", tt.value, err)
+			}
+			decoded := target.Elem().Interface()
+			if want, ok := tt.value.(*big.Int); ok {
+				if decoded.(*big.Int).Cmp(want) != 0 {
+					t.Errorf("Decoded %v, want %v", decoded, want)
+				}
+			} else if _, generic := tt.value.([]interface{}); !generic && !reflect.DeepEqual(decoded, tt.value) {
+				t.Errorf("Decoded %v, want %v", decoded, tt.value)
+			}
+		})
+	}
+}
+
+func TestDecodeStruct(t *testing.T) {
+	input := unhex("cf0983646f678203e8800184deadbeef")
+
+	var got testStruct
+	if err := DecodeBytes(input, &got); err != nil {
+		t.Fatalf("DecodeBytes failed: %v", err)
+	}
+
+	want := testStruct{
+		Nonce:   9,
+		Name:    "dog",
+		Amount:  big.NewInt(1000),
+		Payload: []byte{},
+		Flag:    true,
+		Hash:    [4]byte{0xde, 0xad, 0xbe, 0xef},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decoded %+v, want %+v", got, want)
+	}
+
+	// Pointers are allocated and RawValue keeps the item's encoding
+	var withRaw struct {
+		Inner *testStruct
+		Raw   RawValue
+		Items []RawValue
+	}
+	input = unhex("d7" + "cf0983646f678203e8800184deadbeef" + "83646f67" + "c20180")
+	if err := DecodeBytes(input, &withRaw); err != nil {
+		t.Fatalf("DecodeBytes failed: %v", err)
+	}
+	if withRaw.Inner == nil || withRaw.Inner.Name != "dog" {
+		t.Errorf("Inner = %+v", withRaw.Inner)
+	}
+	if hex.EncodeToString(withRaw.Raw) != "83646f67" {
+		t.Errorf("Raw = %x, want 83646f67", withRaw.Raw)
+	}
+	if len(withRaw.Items) != 2 || hex.EncodeToString(withRaw.Items[0]) != "01" || hex.EncodeToString(withRaw.Items[1]) != "80" {
+		t.Errorf("Items = %x", withRaw.Items)
+	}
+}
+
+func TestDecodeIntegers(t *testing.T) {
+	var u8 uint8
+	var u16 uint16
+	var u64 uint64
+	var b big.Int
+	var flag bool
+
+	tests := []struct {
+		input string
+		into  interface{}
+		want  interface{}
+	}{
+		{"80", &u64, uint64(0)},
+		{"7f", &u64, uint64(127)},
+		{"8180", &u64, uint64(128)},
+		{"88ffffffffffffffff", &u64, ^uint64(0)},
+		{"81ff", &u8, uint8(255)},
+		{"820102", &u16, uint16(0x0102)},
+		{"01", &flag, true},
+		{"80", &flag, false},
+	}
+	for _, tt := range tests {
+		if err := DecodeBytes(unhex(tt.input), tt.into); err != nil {
+			t.Errorf("DecodeBytes(%s) failed: %v", tt.input, err)
+			continue
+		}
+		if got := reflect.ValueOf(tt.into).Elem().Interface(); got != tt.want {
+			t.Errorf("DecodeBytes(%s) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if err := DecodeBytes(unhex("a1010000000000000000000000000000000000000000000000000000000000000000"), &b); err != nil {
+		t.Fatalf("DecodeBytes(big.Int) failed: %v", err)
+	}
+	if want := new(big.Int).Lsh(big.NewInt(1), 256); b.Cmp(want) != 0 {
+		t.Errorf("Decoded %v, want %v", &b, want)
+	}
+}
+
+func TestDecodeNonCanonical(t *testing.T) {
+	var u8 uint8
+	var u64 uint64
+	var b *big.Int
+	var s string
+	var bs []byte
+	var arr [4]byte
+	var list []uint64
+	var fixed [2]uint64
+	var flag bool
+	var st testStruct
+	var generic interface{}
+
+	tests := []struct {
+		name  string
+		input string
+		into  interface{}
+		err   error
+	}{
+		{"empty input", "", &generic, ErrValueTooLarge},
+		{"single byte in string", "8100", &bs, ErrCanonSize},
+		{"single byte 7f in string", "817f", &bs, ErrCanonSize},
+		{"long form short string", "b80100", &bs, ErrCanonSize},
+		{"long form 55 bytes", "b837" + strings.Repeat("61", 55), &s, ErrCanonSize},
+		{"size with leading zero", "b90038" + strings.Repeat("61", 56), &s, ErrCanonSize},
+		{"long form short list", "f80180", &list, ErrCanonSize},
+		{"list size leading zero", "f90038" + strings.Repeat("80", 56), &list, ErrCanonSize},
+		{"truncated string", "8f1020", &bs, ErrValueTooLarge},
+		{"truncated size", "b9", &bs, ErrValueTooLarge},
+		{"truncated list", "c38080", &list, ErrValueTooLarge},
+		{"huge size", "bf0fffffffffffffff", &bs, ErrValueTooLarge},
+		{"integer leading zero", "820001", &u64, ErrCanonInt},
+		{"zero as 00", "00", &u64, ErrCanonInt},
+		{"big integer leading zero", "820001", &b, ErrCanonInt},
+		{"uint8 overflow", "820100", &u8, ErrUintOverflow},
+		{"uint64 overflow", "89010000000000000000", &u64, ErrUintOverflow},
+		{"list for string", "c0", &s, ErrExpectedString},
+		{"list for integer", "c0", &u64, ErrExpectedString},
+		{"string for list", "80", &list, ErrExpectedList},
+		{"string for struct", "80", &st, ErrExpectedList},
+		{"trailing data", "8080", &bs, ErrMoreThanOneValue},
+		{"bad element", "c3820001", &list, ErrCanonInt},
+		{"too few array elements", "c101", &fixed, ErrTooFewElements},
+		{"too many array elements", "c3010203", &fixed, ErrTooManyElements},
+		{"too few struct fields", "c209" + "80", &st, ErrTooFewElements},
+		{"too many struct fields", "d0" + "0983646f678203e8800184deadbeef" + "80", &st, ErrTooManyElements},
+		{"nested non-canonical", "c28100", &generic, ErrCanonSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DecodeBytes(unhex(tt.input), tt.into); !errors.Is(err, tt.err) {
+				t.Errorf("DecodeBytes(%s) error = %v, want %v", tt.input, err, tt.err)
+			}
+		})
+	}
+
+	// Errors without a sentinel
+	if err := DecodeBytes(unhex("83010203"), &arr); err == nil {
+		t.Error("Byte array length mismatch accepted")
+	}
+	if err := DecodeBytes(unhex("02"), &flag); err == nil {
+		t.Error("Boolean value 2 accepted")
+	}
+	if err := DecodeBytes(unhex("80"), u64); err == nil {
+		t.Error("Non-pointer target accepted")
+	}
+	var i64 int64
+	if err := DecodeBytes(unhex("01"), &i64); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Expected ErrUnsupportedType, got %v", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input   string
+		kind    Kind
+		content string
+		rest    string
+	}{
+		{"00", Byte, "00", ""},
+		{"7f01", Byte, "7f", "01"},
+		{"80", String, "", ""},
+		{"8180", String, "80", ""},
+		{"83646f6701", String, "646f67", "01"},
+		{"c0", List, "", ""},
+		{"c20180c0", List, "0180", "c0"},
+	}
+	for _, tt := range tests {
+		kind, content, rest, err := Split(unhex(tt.input))
+		if err != nil {
+			t.Errorf("Split(%s) failed: %v", tt.input, err)
+			continue
+		}
+		if kind != tt.kind || hex.EncodeToString(content) != tt.content || hex.EncodeToString(rest) != tt.rest {
+			t.Errorf("Split(%s) = %v, %x, %x; want %v, %s, %s", tt.input, kind, content, rest, tt.kind, tt.content, tt.rest)
+		}
+	}
+
+	if _, _, err := SplitString(unhex("c0")); err != ErrExpectedString {
+		t.Errorf("SplitString(c0) error = %v", err)
+	}
+	if _, _, err := SplitList(unhex("80")); err != ErrExpectedList {
+		t.Errorf("SplitList(80) error = %v", err)
+	}
+
+	count, err := CountValues(unhex("0180c0c20102"))
+	if err != nil || count != 4 {
+		t.Errorf("CountValues = %d, %v; want 4", count, err)
+	}
+	if _, err := CountValues(unhex("0182")); err != ErrValueTooLarge {
+		t.Errorf("CountValues error = %v, want ErrValueTooLarge", err)
+	}
+}
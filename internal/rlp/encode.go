@@ -0,0 +1,216 @@
+package rlp
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	rawValueType = reflect.TypeOf(RawValue{})
+)
+
+// Encode writes the RLP encoding of val to w
+func Encode(w io.Writer, val interface{}) error {
+	b, err := EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// EncodeToBytes returns the RLP encoding of val
+func EncodeToBytes(val interface{}) ([]byte, error) {
+	return appendValue(nil, reflect.ValueOf(val))
+}
+
+// AppendString appends the encoding of the byte string s to b
+func AppendString(b, s []byte) []byte {
+	if len(s) == 1 && s[0] < 0x80 {
+		return append(b, s[0])
+	}
+	b = appendHeader(b, 0x80, uint64(len(s)))
+	return append(b, s...)
+}
+
+// AppendUint64 appends the encoding of the integer i to b
+func AppendUint64(b []byte, i uint64) []byte {
+	switch {
+	case i == 0:
+		return append(b, 0x80)
+	case i < 0x80:
+		return append(b, byte(i))
+	}
+
+	var buf [8]byte
+	n := putUint(buf[:], i)
+	b = append(b, 0x80+byte(n))
+	return append(b, buf[8-n:]...)
+}
+
+// AppendBigInt appends the encoding of the non-negative integer i to b
+func AppendBigInt(b []byte, i *big.Int) ([]byte, error) {
+	if i.Sign() < 0 {
+		return nil, fmt.Errorf("%w: negative big.Int", ErrUnsupportedType)
+	}
+	if i.BitLen() <= 64 {
+		return AppendUint64(b, i.Uint64()), nil
+	}
+	return AppendString(b, i.Bytes()), nil
+}
+
+// AppendList appends a list header for content followed by content, which
+// must be the concatenated encodings of the list items
+func AppendList(b, content []byte) []byte {
+	b = appendHeader(b, 0xC0, uint64(len(content)))
+	return append(b, content...)
+}
+
+// appendHeader appends a string (base 0x80) or list (base 0xC0) header for a
+// payload of the given size
+func appendHeader(b []byte, base byte, size uint64) []byte {
+	if size < 56 {
+		return append(b, base+byte(size))
+	}
+
+	var buf [8]byte
+	n := putUint(buf[:], size)
+	b = append(b, base+55+byte(n))
+	return append(b, buf[8-n:]...)
+}
+
+// putUint writes i big-endian into the end of buf without leading zeros and
+// returns the number of bytes used
+func putUint(buf []byte, i uint64) int {
+	n := 0
+	for v := i; v > 0; v >>= 8 {
+		n++
+	}
+	for j := 0; j < n; j++ {
+		buf[len(buf)-1-j] = byte(i >> (8 * j))
+	}
+	return n
+}
+
+// appendValue appends the encoding of v to b
+func appendValue(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(b, EmptyList...), nil
+	}
+
+	typ := v.Type()
+	switch {
+	case typ == rawValueType:
+		return append(b, v.Bytes()...), nil
+	case typ == bigIntType:
+		i := v.Interface().(big.Int)
+		return AppendBigInt(b, &i)
+	case typ.Kind() == reflect.Ptr && typ.Elem() == bigIntType:
+		if v.IsNil() {
+			return append(b, EmptyString...), nil
+		}
+		return AppendBigInt(b, v.Interface().(*big.Int))
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 0x01), nil
+		}
+		return append(b, 0x80), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return AppendUint64(b, v.Uint()), nil
+
+	case reflect.String:
+		return AppendString(b, []byte(v.String())), nil
+
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return AppendString(b, v.Bytes()), nil
+		}
+		return appendList(b, v.Len(), v.Index)
+
+	case reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			content := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(content), v)
+			return AppendString(b, content), nil
+		}
+		return appendList(b, v.Len(), v.Index)
+
+	case reflect.Struct:
+		fields, err := structFields(typ)
+		if err != nil {
+			return nil, err
+		}
+		return appendList(b, len(fields), func(i int) reflect.Value {
+			return v.Field(fields[i])
+		})
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return appendEmpty(b, typ.Elem())
+		}
+		return appendValue(b, v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(b, EmptyList...), nil
+		}
+		return appendValue(b, v.Elem())
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, typ)
+}
+
+// appendList appends a list of n items returned by item
+func appendList(b []byte, n int, item func(int) reflect.Value) ([]byte, error) {
+	var content []byte
+	var err error
+	for i := 0; i < n; i++ {
+		if content, err = appendValue(content, item(i)); err != nil {
+			return nil, err
+		}
+	}
+	return AppendList(b, content), nil
+}
+
+// appendEmpty appends the encoding of a nil pointer to typ: the empty list
+// for list-like types and the empty string otherwise
+func appendEmpty(b []byte, typ reflect.Type) ([]byte, error) {
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Interface:
+		if typ != bigIntType {
+			return append(b, EmptyList...), nil
+		}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() != reflect.Uint8 {
+			return append(b, EmptyList...), nil
+		}
+	}
+	return append(b, EmptyString...), nil
+}
+
+// structFields returns the indexes of the exported fields of a struct type
+// that take part in encoding
+func structFields(typ reflect.Type) ([]int, error) {
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		switch tag := field.Tag.Get("rlp"); tag {
+		case "":
+			fields = append(fields, i)
+		case "-":
+		default:
+			return nil, fmt.Errorf("rlp: unknown struct tag %q on %v.%s", tag, typ, field.Name)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,209 @@
+package rlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func mustBig(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid integer " + s)
+	}
+	return i
+}
+
+var (
+	listOfThree = []string{"asdf", "qwer", "zxcv"}
+	encodedList = "cf84617364668471776572847a786376"
+)
+
+// Vectors from the Ethereum RLP specification and the rlptest.json suite of
+// ethereum/tests
+var encodeVectors = []struct {
+	name  string
+	value interface{}
+	want  string
+}{
+	{"emptystring", "", "80"},
+	{"bytestring00", []byte{0x00}, "00"},
+	{"bytestring01", []byte{0x01}, "01"},
+	{"bytestring7F", []byte{0x7F}, "7f"},
+	{"bytestring80", []byte{0x80}, "8180"},
+	{"shortstring", "dog", "83646f67"},
+	{"shortstring2", "Lorem ipsum dolor sit amet, consectetur adipisicing eli",
+		"b74c6f72656d20697073756d20646f6c6f722073697420616d65742c20636f6e7365637465747572206164697069736963696e6720656c69"},
+	{"longstring", "Lorem ipsum dolor sit amet, consectetur adipisicing elit",
+		"b8384c6f72656d20697073756d20646f6c6f722073697420616d65742c20636f6e7365637465747572206164697069736963696e6720656c6974"},
+	{"longstring2", strings.Repeat("a", 1024), "b90400" + strings.Repeat("61", 1024)},
+	{"zero", uint64(0), "80"},
+	{"smallint", uint64(1), "01"},
+	{"smallint2", uint64(16), "10"},
+	{"smallint3", uint64(79), "4f"},
+	{"smallint4", uint64(127), "7f"},
+	{"mediumint1", uint64(128), "8180"},
+	{"mediumint2", uint64(1000), "8203e8"},
+	{"mediumint3", uint64(100000), "830186a0"},
+	{"mediumint4", mustBig("83729609699884896815286331701780722"), "8f102030405060708090a0b0c0d0e0f2"},
+	{"mediumint5", mustBig("105315505618206987246253880190783558935785933862974822347068935681"),
+		"9c0100020003000400050006000700080009000a000b000c000d000e01"},
+	{"bigint", mustBig("115792089237316195423570985008687907853269984665640564039457584007913129639936"),
+		"a1010000000000000000000000000000000000000000000000000000000000000000"},
+	{"emptylist", []string{}, "c0"},
+	{"stringlist", []string{"dog", "god", "cat"}, "cc83646f6783676f6483636174"},
+	{"multilist", []interface{}{"zw", []interface{}{uint64(4)}, uint64(1)}, "c6827a77c10401"},
+	{"shortListMax1", []string{"asdf", "qwer", "zxcv", "asdf", "qwer", "zxcv", "asdf", "qwer", "zxcv", "asdf", "qwer"},
+		"f784617364668471776572847a78637684617364668471776572847a78637684617364668471776572847a78637684617364668471776572"},
+	{"longList1", [][]string{listOfThree, listOfThree, listOfThree, listOfThree},
+		"f840" + strings.Repeat(encodedList, 4)},
+	{"longList2", func() [][]string {
+		list := make([][]string, 32)
+		for i := range list {
+			list[i] = listOfThree
+		}
+		return list
+	}(), "f90200" + strings.Repeat(encodedList, 32)},
+	{"listsoflists", []interface{}{[]interface{}{[]interface{}{}, []interface{}{}}, []interface{}{}}, "c4c2c0c0c0"},
+	{"listsoflists2", []interface{}{
+		[]interface{}{},
+		[]interface{}{[]interface{}{}},
+		[]interface{}{[]interface{}{}, []interface{}{[]interface{}{}}},
+	}, "c7c0c1c0c3c0c1c0"},
+	{"dictTest1", [][]string{{"key1", "val1"}, {"key2", "val2"}, {"key3", "val3"}, {"key4", "val4"}},
+		"ecca846b6579318476616c31ca846b6579328476616c32ca846b6579338476616c33ca846b6579348476616c34"},
+}
+
+func TestEncodeVectors(t *testing.T) {
+	for _, tt := range encodeVectors {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeToBytes(tt.value)
+			if err != nil {
+				t.Fatalf("EncodeToBytes failed: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("EncodeToBytes = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+type testStruct struct {
+	Nonce   uint64
+	Name    string
+	Amount  *big.Int
+	Payload []byte
+	Flag    bool
+	Hash    [4]byte
+	Ignored string `rlp:"-"`
+	private uint64
+}
+
+func TestEncodeTypes(t *testing.T) {
+	var nilBig *big.Int
+	var nilBytes *[]byte
+	var nilStruct *testStruct
+	var nilList *[]uint64
+	var nilInterface interface{}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"uint8", uint8(200), "81c8"},
+		{"uint16", uint16(0x0102), "820102"},
+		{"uint32", uint32(0x01000000), "8401000000"},
+		{"uint max", ^uint64(0), "88ffffffffffffffff"},
+		{"bool true", true, "01"},
+		{"bool false", false, "80"},
+		{"big.Int value", *big.NewInt(1024), "820400"},
+		{"big.Int zero", big.NewInt(0), "80"},
+		{"byte array", [3]byte{1, 2, 3}, "83010203"},
+		{"single byte array", [1]byte{0x05}, "05"},
+		{"uint list", []uint64{1, 2, 1024}, "c50102820400"},
+		{"raw value", []RawValue{{0x83, 'd', 'o', 'g'}, {0xC0}}, "c583646f67c0"},
+		{"nil big.Int", nilBig, "80"},
+		{"nil bytes", nilBytes, "80"},
+		{"nil struct", nilStruct, "c0"},
+		{"nil list", nilList, "c0"},
+		{"nil interface", nilInterface, "c0"},
+		{"struct", testStruct{
+			Nonce:   9,
+			Name:    "dog",
+			Amount:  big.NewInt(1000),
+			Payload: []byte{},
+			Flag:    true,
+			Hash:    [4]byte{0xde, 0xad, 0xbe, 0xef},
+			Ignored: "skipped",
+			private: 7,
+		}, "cf0983646f678203e8800184deadbeef"},
+		{"struct pointer", &testStruct{}, "ca808080808084" + "00000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeToBytes(tt.value)
+			if err != nil {
+				t.Fatalf("EncodeToBytes failed: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("EncodeToBytes = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	type badTag struct {
+		A uint64 `rlp:"optional"`
+	}
+
+	values := []interface{}{
+		big.NewInt(-1),
+		int64(1),
+		1.5,
+		map[string]string{},
+		badTag{},
+	}
+	for _, value := range values {
+		if _, err := EncodeToBytes(value); err == nil {
+			t.Errorf("EncodeToBytes(%T) succeeded, want error", value)
+		}
+	}
+
+	if _, err := EncodeToBytes(big.NewInt(-1)); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Expected ErrUnsupportedType, got %v", err)
+	}
+}
+
+func TestEncodeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, []string{"cat", "dog"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if got := hex.EncodeToString(buf.Bytes()); got != "c88363617483646f67" {
+		t.Errorf("Encode = %s, want c88363617483646f67", got)
+	}
+}
+
+func TestAppendHelpers(t *testing.T) {
+	var content []byte
+	content = AppendUint64(content, 0)
+	content = AppendUint64(content, 127)
+	content = AppendUint64(content, 1024)
+	content = AppendString(content, []byte("dog"))
+	content, err := AppendBigInt(content, mustBig("83729609699884896815286331701780722"))
+	if err != nil {
+		t.Fatalf("AppendBigInt failed: %v", err)
+	}
+
+	got := hex.EncodeToString(AppendList(nil, content))
+	want := "d9807f82040083646f678f102030405060708090a0b0c0d0e0f2"
+	if got != want {
+		t.Errorf("AppendList = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+// Package rlp implements Recursive Length Prefix encoding as specified in the
+// Ethereum Yellow Paper, appendix B.
+//
+// RLP encodes two kinds of items: byte strings and lists of items. Go values
+// map onto them as follows:
+//
+//   - []byte, [N]byte and string encode as byte strings
+//   - unsigned integers, *big.Int and big.Int encode as byte strings holding
+//     the big-endian value without leading zeros; zero is the empty string
+//   - bool encodes as the integer 0 or 1
+//   - slices, arrays and structs encode as lists of their elements or
+//     exported fields; a field tagged `rlp:"-"` is skipped
+//   - RawValue is copied verbatim and is assumed to be valid RLP
+//   - a nil pointer encodes as the empty item of its element type
+//
+// Decoding rejects every non-canonical form: single bytes below 0x80 wrapped
+// in a string header, long headers for short payloads, sizes and integers
+// with leading zero bytes, and trailing input.
+package rlp
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Decoding errors
+var (
+	ErrExpectedString   = errors.New("rlp: expected string or byte")
+	ErrExpectedList     = errors.New("rlp: expected list")
+	ErrCanonInt         = errors.New("rlp: non-canonical integer (leading zero bytes)")
+	ErrCanonSize        = errors.New("rlp: non-canonical size information")
+	ErrValueTooLarge    = errors.New("rlp: value size exceeds available input length")
+	ErrUintOverflow     = errors.New("rlp: uint overflow")
+	ErrMoreThanOneValue = errors.New("rlp: input contains more than one value")
+	ErrTooFewElements   = errors.New("rlp: too few elements")
+	ErrTooManyElements  = errors.New("rlp: too many elements")
+	ErrUnsupportedType  = errors.New("rlp: unsupported type")
+)
+
+// Kind is the type of an RLP item
+type Kind int
+
+// RLP item kinds
+const (
+	// Byte is a single byte below 0x80 encoded as itself
+	Byte Kind = iota
+	// String is a byte string with a length prefix
+	String
+	// List is a list of items with a length prefix
+	List
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case Byte:
+		return "Byte"
+	case String:
+		return "String"
+	case List:
+		return "List"
+	}
+	return "Unknown"
+}
+
+// RawValue is an already encoded RLP item. It is written as is when encoding
+// and receives the complete encoding of an item when decoding.
+type RawValue []byte
+
+// EmptyString and EmptyList are the encodings of "" and []
+var (
+	EmptyString = []byte{0x80}
+	EmptyList   = []byte{0xC0}
+)
+
+// Split reads the first item of b and returns its kind, its payload and the
+// input following it. The header is checked for canonical form.
+func Split(b []byte) (kind Kind, content, rest []byte, err error) {
+	if len(b) == 0 {
+		return 0, nil, nil, ErrValueTooLarge
+	}
+
+	prefix := b[0]
+	var offset, size uint64
+	switch {
+	case prefix < 0x80:
+		return Byte, b[:1], b[1:], nil
+
+	case prefix < 0xB8:
+		kind, offset, size = String, 1, uint64(prefix-0x80)
+		// A single byte below 0x80 must be encoded as itself
+		if size == 1 && len(b) > 1 && b[1] < 0x80 {
+			return 0, nil, nil, ErrCanonSize
+		}
+
+	case prefix < 0xC0:
+		kind, offset = String, 1+uint64(prefix-0xB7)
+		size, err = readSize(b[1:], prefix-0xB7)
+
+	case prefix < 0xF8:
+		kind, offset, size = List, 1, uint64(prefix-0xC0)
+
+	default:
+		kind, offset = List, 1+uint64(prefix-0xF7)
+		size, err = readSize(b[1:], prefix-0xF7)
+	}
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	if size > uint64(len(b))-offset {
+		return 0, nil, nil, ErrValueTooLarge
+	}
+	return kind, b[offset : offset+size], b[offset+size:], nil
+}
+
+// readSize decodes the big-endian length of a long string or list header,
+// which must not have leading zeros and must be at least 56
+func readSize(b []byte, lenOfSize byte) (uint64, error) {
+	if int(lenOfSize) > len(b) {
+		return 0, ErrValueTooLarge
+	}
+	if b[0] == 0 {
+		return 0, ErrCanonSize
+	}
+
+	var buf [8]byte
+	copy(buf[8-lenOfSize:], b[:lenOfSize])
+	size := binary.BigEndian.Uint64(buf[:])
+	if size < 56 {
+		return 0, ErrCanonSize
+	}
+	return size, nil
+}
+
+// SplitString splits b into the payload of a byte string and the rest
+func SplitString(b []byte) (content, rest []byte, err error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind == List {
+		return nil, nil, ErrExpectedString
+	}
+	return content, rest, nil
+}
+
+// SplitList splits b into the payload of a list and the rest
+func SplitList(b []byte) (content, rest []byte, err error) {
+	kind, content, rest, err := Split(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != List {
+		return nil, nil, ErrExpectedList
+	}
+	return content, rest, nil
+}
+
+// CountValues returns the number of items encoded in b
+func CountValues(b []byte) (int, error) {
+	count := 0
+	for len(b) > 0 {
+		_, _, rest, err := Split(b)
+		if err != nil {
+			return 0, err
+		}
+		b = rest
+		count++
+	}
+	return count, nil
+}
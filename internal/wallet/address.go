@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Address parsing errors
+var (
+	ErrInvalidAddress         = errors.New("invalid address")
+	ErrInvalidAddressChecksum = errors.New("invalid address checksum")
+)
+
+// checksumHex returns the EIP-55 encoding of the address: a hex letter is
+// upper-cased when the matching nibble of Keccak-256 of the lowercase hex
+// digits is 8 or greater.
+func (a Address) checksumHex() string {
+	buf := make([]byte, 2+2*AddressLength)
+	copy(buf, "0x")
+	digits := buf[2:]
+	hex.Encode(digits, a[:])
+
+	hash := keccak256(digits)
+	for i, c := range digits {
+		if c < 'a' {
+			continue
+		}
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0F >= 8 {
+			digits[i] = c - 'a' + 'A'
+		}
+	}
+
+	return string(buf)
+}
+
+// ParseAddress parses a 20-byte hex address with an optional 0x prefix. An
+// all-lowercase or all-uppercase address is accepted as is; a mixed-case
+// address must carry a valid EIP-55 checksum.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(digits) != 2*AddressLength {
+		return a, fmt.Errorf("%w: %q has %d hex digits, want %d", ErrInvalidAddress, s, len(digits), 2*AddressLength)
+	}
+	if _, err := hex.Decode(a[:], []byte(digits)); err != nil {
+		return Address{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, s, err)
+	}
+
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if a.checksumHex()[2:] != digits {
+			return Address{}, fmt.Errorf("%w: %q", ErrInvalidAddressChecksum, s)
+		}
+	}
+
+	return a, nil
+}
+
+// HexToAddress parses an address with ParseAddress and panics on error
+func HexToAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// MarshalText implements encoding.TextMarshaler using the EIP-55 encoding
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.checksumHex()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseAddress
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
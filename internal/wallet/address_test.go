@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// EIP-55 reference addresses
+var eip55Vectors = []string{
+	// All caps
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	// All lower
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	// Normal
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestAddressChecksum(t *testing.T) {
+	for _, want := range eip55Vectors {
+		t.Run(want, func(t *testing.T) {
+			addr, err := ParseAddress(want)
+			if err != nil {
+				t.Fatalf("ParseAddress failed: %v", err)
+			}
+			if got := addr.Hex(); got != want {
+				t.Errorf("Hex() = %s, want %s", got, want)
+			}
+
+			// Single-case forms carry no checksum and are always accepted
+			for _, s := range []string{strings.ToLower(want), "0x" + strings.ToUpper(want[2:]), want[2:]} {
+				parsed, err := ParseAddress(s)
+				if err != nil {
+					t.Errorf("ParseAddress(%s) failed: %v", s, err)
+				} else if parsed != addr {
+					t.Errorf("ParseAddress(%s) = %s, want %s", s, parsed.Hex(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{"empty", "", ErrInvalidAddress},
+		{"prefix only", "0x", ErrInvalidAddress},
+		{"too short", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", ErrInvalidAddress},
+		{"too long", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", ErrInvalidAddress},
+		{"odd length", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", ErrInvalidAddress},
+		{"not hex", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", ErrInvalidAddress},
+		{"bad checksum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAEd", ErrInvalidAddressChecksum},
+		{"swapped case", "0x5AaEB6053f3e94c9B9a09F33669435e7eF1bEaED", ErrInvalidAddressChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAddress(tt.address); !errors.Is(err, tt.err) {
+				t.Errorf("ParseAddress(%q) error = %v, want %v", tt.address, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHexToAddress(t *testing.T) {
+	addr := HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359")
+	if addr.Hex() != "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359" {
+		t.Errorf("HexToAddress = %s", addr.Hex())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("HexToAddress did not panic on an invalid address")
+		}
+	}()
+	HexToAddress("0x1234")
+}
+
+func TestAddressJSON(t *testing.T) {
+	type config struct {
+		Owner   Address   `json:"owner"`
+		Signers []Address `json:"signers"`
+	}
+
+	input := `{"owner":"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","signers":["0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"]}`
+	var cfg config
+	if err := json.Unmarshal([]byte(input), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if cfg.Owner != HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") {
+		t.Errorf("Owner = %s", cfg.Owner.Hex())
+	}
+
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"owner":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed","signers":["0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb"]}`
+	if string(out) != want {
+		t.Errorf("Marshal = %s, want %s", out, want)
+	}
+
+	// Map keys use the text encoding as well
+	keyed := map[Address]int{cfg.Owner: 1}
+	if out, err := json.Marshal(keyed); err != nil || string(out) != `{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed":1}` {
+		t.Errorf("Marshal map = %s, %v", out, err)
+	}
+
+	bad := `{"owner":"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAEd"}`
+	if err := json.Unmarshal([]byte(bad), &cfg); !errors.Is(err, ErrInvalidAddressChecksum) {
+		t.Errorf("Expected ErrInvalidAddressChecksum, got %v", err)
+	}
+}
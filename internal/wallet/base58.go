@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// base58Alphabet is the Bitcoin base58 alphabet used by BIP-32 serialization
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	errInvalidBase58   = errors.New("invalid base58 string")
+	errInvalidChecksum = errors.New("invalid base58 checksum")
+)
+
+// base58Decode maps base58 characters to their values
+var base58Decode [256]int8
+
+func init() {
+	for i := range base58Decode {
+		base58Decode[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58Decode[base58Alphabet[i]] = int8(i)
+	}
+}
+
+// base58Encode encodes data as a base58 string, preserving leading zero bytes
+func base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	// Digits were produced least significant first
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// base58DecodeString decodes a base58 string, preserving leading zero bytes
+func base58DecodeString(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := base58Decode[s[i]]
+		if digit < 0 {
+			return nil, errInvalidBase58
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	decoded := n.Bytes()
+	result := make([]byte, zeros+len(decoded))
+	copy(result[zeros:], decoded)
+	return result, nil
+}
+
+// base58CheckEncode appends a double SHA-256 checksum and base58 encodes the result
+func base58CheckEncode(payload []byte) string {
+	checksum := doubleSHA256(payload)
+	data := make([]byte, 0, len(payload)+4)
+	data = append(data, payload...)
+	data = append(data, checksum[:4]...)
+	encoded := base58Encode(data)
+	secureClear(data)
+	return encoded
+}
+
+// base58CheckDecode decodes a base58check string and verifies its checksum
+func base58CheckDecode(s string) ([]byte, error) {
+	data, err := base58DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, errInvalidBase58
+	}
+
+	payload, checksum := data[:len(data)-4], data[len(data)-4:]
+	expected := doubleSHA256(payload)
+	for i := range checksum {
+		if checksum[i] != expected[i] {
+			secureClear(data)
+			return nil, errInvalidChecksum
+		}
+	}
+	return payload, nil
+}
+
+// doubleSHA256 returns SHA-256(SHA-256(data))
+func doubleSHA256(data []byte) [32]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	// Test vectors from Bitcoin Core's base58_encode_decode.json
+	tests := []struct {
+		hex     string
+		encoded string
+	}{
+		{"", ""},
+		{"61", "2g"},
+		{"626262", "a3gV"},
+		{"636363", "aPEr"},
+		{"73696d706c792061206c6f6e6720737472696e67", "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{"00eb15231dfceb60925886b67d065299925915aeb172c06647", "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{"516b6fcd0f", "ABnLTmg"},
+		{"bf4f89001e670274dd", "3SEo3LWLoPntC"},
+		{"572e4794", "3EFU7m"},
+		{"ecac89cad93923c02321", "EJDM8drfXA6uyA"},
+		{"10c8511e", "Rt5zm"},
+		{"00000000000000000000", "1111111111"},
+	}
+
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.hex)
+		if got := base58Encode(data); got != tt.encoded {
+			t.Errorf("base58Encode(%s) = %q, want %q", tt.hex, got, tt.encoded)
+		}
+
+		decoded, err := base58DecodeString(tt.encoded)
+		if err != nil {
+			t.Errorf("base58DecodeString(%q) failed: %v", tt.encoded, err)
+			continue
+		}
+		if hex.EncodeToString(decoded) != tt.hex {
+			t.Errorf("base58DecodeString(%q) = %x, want %s", tt.encoded, decoded, tt.hex)
+		}
+	}
+
+	// Characters outside the alphabet
+	for _, s := range []string{"0", "O", "I", "l", "3SEo3LWLoPnt!"} {
+		if _, err := base58DecodeString(s); err != errInvalidBase58 {
+			t.Errorf("base58DecodeString(%q) = %v, want %v", s, err, errInvalidBase58)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	// Version byte and HASH160 from the Bitcoin wiki address example
+	payload, _ := hex.DecodeString("00010966776006953d5567439e5e39f86a0d273bee")
+	encoded := base58CheckEncode(payload)
+	if encoded != "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM" {
+		t.Errorf("base58CheckEncode() = %q, want %q", encoded, "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM")
+	}
+
+	decoded, err := base58CheckDecode(encoded)
+	if err != nil {
+		t.Fatalf("base58CheckDecode() failed: %v", err)
+	}
+	if hex.EncodeToString(decoded) != hex.EncodeToString(payload) {
+		t.Errorf("base58CheckDecode() = %x, want %x", decoded, payload)
+	}
+
+	// Corrupt the last character
+	if _, err := base58CheckDecode("16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"); err != errInvalidChecksum {
+		t.Errorf("base58CheckDecode() with bad checksum = %v, want %v", err, errInvalidChecksum)
+	}
+	if _, err := base58CheckDecode("2g"); err != errInvalidBase58 {
+		t.Errorf("base58CheckDecode() with short input = %v, want %v", err, errInvalidBase58)
+	}
+}
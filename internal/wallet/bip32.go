@@ -0,0 +1,340 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// BIP-32 constants
+const (
+	// HardenedKeyStart is the index of the first hardened child key
+	HardenedKeyStart = 0x80000000
+	// MinSeedBytes is the minimum seed length accepted by BIP-32 (128 bits)
+	MinSeedBytes = 16
+	// MaxSeedBytes is the maximum seed length accepted by BIP-32 (512 bits)
+	MaxSeedBytes = 64
+
+	// serializedKeyLength is the length of a serialized extended key payload
+	serializedKeyLength = 78
+)
+
+// BIP-32 errors
+var (
+	ErrInvalidExtendedKey = errors.New("invalid extended key")
+	ErrHardenedFromPublic = errors.New("cannot derive a hardened key from a public key")
+	ErrInvalidChildKey    = errors.New("derived child key is invalid")
+	ErrMaxDepth           = errors.New("maximum derivation depth exceeded")
+	ErrNotPrivate         = errors.New("extended key is not private")
+)
+
+// Extended key versions for Bitcoin mainnet serialization (xprv/xpub)
+var (
+	xprvVersion = []byte{0x04, 0x88, 0xAD, 0xE4}
+	xpubVersion = []byte{0x04, 0x88, 0xB2, 0x1E}
+)
+
+// masterHMACKey is the HMAC key used to derive the master node from a seed
+var masterHMACKey = []byte("Bitcoin seed")
+
+// ExtendedKey is a BIP-32 extended private or public key
+type ExtendedKey struct {
+	key       []byte // 32-byte private key or 33-byte compressed public key
+	pubKey    []byte // 33-byte compressed public key
+	chainCode []byte
+	parentFP  []byte
+	depth     uint8
+	childNum  uint32
+	isPrivate bool
+}
+
+// NewMasterKey creates the BIP-32 master node from a seed of 16 to 64 bytes
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < MinSeedBytes || len(seed) > MaxSeedBytes {
+		return nil, ErrInvalidSeed
+	}
+
+	mac := hmac.New(sha512.New, masterHMACKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	defer secureClear(sum)
+
+	// The master secret key must be in [1, n-1]
+	keyInt := new(big.Int).SetBytes(sum[:32])
+	if keyInt.Sign() == 0 || keyInt.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("%w: seed produces an invalid master key", ErrInvalidSeed)
+	}
+
+	key := &ExtendedKey{
+		key:       append([]byte(nil), sum[:32]...),
+		chainCode: append([]byte(nil), sum[32:]...),
+		parentFP:  []byte{0, 0, 0, 0},
+		isPrivate: true,
+	}
+	key.pubKey = privateToCompressed(key.key)
+	return key, nil
+}
+
+// IsPrivate reports whether the extended key holds a private key
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.isPrivate
+}
+
+// Depth returns the number of derivation steps from the master node
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// ChildIndex returns the index this key was derived at from its parent
+func (k *ExtendedKey) ChildIndex() uint32 {
+	return k.childNum
+}
+
+// ChainCode returns a copy of the key's chain code
+func (k *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), k.chainCode...)
+}
+
+// PrivateKeyBytes returns a copy of the 32-byte private key
+func (k *ExtendedKey) PrivateKeyBytes() ([]byte, error) {
+	if !k.isPrivate {
+		return nil, ErrNotPrivate
+	}
+	return append([]byte(nil), k.key...), nil
+}
+
+// PublicKeyBytes returns the 33-byte compressed public key
+func (k *ExtendedKey) PublicKeyBytes() []byte {
+	return append([]byte(nil), k.pubKey...)
+}
+
+// Fingerprint returns the first four bytes of HASH160 of the public key
+func (k *ExtendedKey) Fingerprint() []byte {
+	sha := sha256.Sum256(k.pubKey)
+	hash := ripemd160Sum(sha[:])
+	return hash[:4]
+}
+
+// Child derives the child key at index i. Indexes at or above
+// HardenedKeyStart produce hardened keys, which require a private parent.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	if k.depth == 255 {
+		return nil, ErrMaxDepth
+	}
+
+	hardened := i >= HardenedKeyStart
+	if hardened && !k.isPrivate {
+		return nil, ErrHardenedFromPublic
+	}
+
+	// Hardened: HMAC-SHA512(c, 0x00 || k || i); normal: HMAC-SHA512(c, K || i)
+	data := make([]byte, 0, 37)
+	if hardened {
+		data = append(data, 0x00)
+		data = append(data, k.key...)
+	} else {
+		data = append(data, k.pubKey...)
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	secureClear(data)
+	defer secureClear(sum)
+
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidChildKey
+	}
+
+	child := &ExtendedKey{
+		chainCode: append([]byte(nil), sum[32:]...),
+		parentFP:  k.Fingerprint(),
+		depth:     k.depth + 1,
+		childNum:  i,
+		isPrivate: k.isPrivate,
+	}
+
+	if k.isPrivate {
+		// k_i = IL + k_par (mod n)
+		keyInt := new(big.Int).SetBytes(k.key)
+		keyInt.Add(keyInt, il)
+		keyInt.Mod(keyInt, secp256k1N)
+		if keyInt.Sign() == 0 {
+			return nil, ErrInvalidChildKey
+		}
+		child.key = keyInt.FillBytes(make([]byte, 32))
+		child.pubKey = privateToCompressed(child.key)
+		keyInt.SetInt64(0)
+	} else {
+		// K_i = point(IL) + K_par
+		px, py, err := secp256k1Decompress(k.pubKey)
+		if err != nil {
+			return nil, err
+		}
+		ix, iy := secp256k1ScalarBaseMult(sum[:32])
+		cx, cy := secp256k1Add(ix, iy, px, py)
+		if cx.Sign() == 0 && cy.Sign() == 0 {
+			return nil, ErrInvalidChildKey
+		}
+		child.key = secp256k1Compress(cx, cy)
+		child.pubKey = child.key
+	}
+	il.SetInt64(0)
+
+	return child, nil
+}
+
+// DerivePath derives the descendant key at path, relative to this key
+func (k *ExtendedKey) DerivePath(path DerivationPath) (*ExtendedKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if key != k {
+			key.Zero()
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	if key == k {
+		return k.clone(), nil
+	}
+	return key, nil
+}
+
+// Neuter returns the public extended key corresponding to this key
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.isPrivate {
+		return k.clone()
+	}
+	return &ExtendedKey{
+		key:       k.PublicKeyBytes(),
+		pubKey:    k.PublicKeyBytes(),
+		chainCode: append([]byte(nil), k.chainCode...),
+		parentFP:  append([]byte(nil), k.parentFP...),
+		depth:     k.depth,
+		childNum:  k.childNum,
+	}
+}
+
+// String returns the base58check serialization (xprv or xpub) of the key
+func (k *ExtendedKey) String() string {
+	payload := make([]byte, 0, serializedKeyLength)
+	if k.isPrivate {
+		payload = append(payload, xprvVersion...)
+	} else {
+		payload = append(payload, xpubVersion...)
+	}
+	payload = append(payload, k.depth)
+	payload = append(payload, k.parentFP...)
+	payload = binary.BigEndian.AppendUint32(payload, k.childNum)
+	payload = append(payload, k.chainCode...)
+	if k.isPrivate {
+		payload = append(payload, 0x00)
+	}
+	payload = append(payload, k.key...)
+
+	encoded := base58CheckEncode(payload)
+	secureClear(payload)
+	return encoded
+}
+
+// ParseExtendedKey parses a base58check serialized xprv or xpub key
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	payload, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+	defer secureClear(payload)
+
+	if len(payload) != serializedKeyLength {
+		return nil, fmt.Errorf("%w: bad length %d", ErrInvalidExtendedKey, len(payload))
+	}
+
+	version := payload[0:4]
+	key := &ExtendedKey{
+		depth:     payload[4],
+		parentFP:  append([]byte(nil), payload[5:9]...),
+		childNum:  binary.BigEndian.Uint32(payload[9:13]),
+		chainCode: append([]byte(nil), payload[13:45]...),
+	}
+	keyData := payload[45:78]
+
+	switch {
+	case bytes.Equal(version, xprvVersion):
+		key.isPrivate = true
+	case bytes.Equal(version, xpubVersion):
+	default:
+		return nil, fmt.Errorf("%w: unknown version %x", ErrInvalidExtendedKey, version)
+	}
+
+	if key.depth == 0 {
+		if !bytes.Equal(key.parentFP, []byte{0, 0, 0, 0}) {
+			return nil, fmt.Errorf("%w: zero depth with non-zero parent fingerprint", ErrInvalidExtendedKey)
+		}
+		if key.childNum != 0 {
+			return nil, fmt.Errorf("%w: zero depth with non-zero index", ErrInvalidExtendedKey)
+		}
+	}
+
+	if key.isPrivate {
+		if keyData[0] != 0x00 {
+			return nil, fmt.Errorf("%w: private key prefix %#02x", ErrInvalidExtendedKey, keyData[0])
+		}
+		keyInt := new(big.Int).SetBytes(keyData[1:])
+		valid := keyInt.Sign() != 0 && keyInt.Cmp(secp256k1N) < 0
+		keyInt.SetInt64(0)
+		if !valid {
+			return nil, fmt.Errorf("%w: private key out of range", ErrInvalidExtendedKey)
+		}
+		key.key = append([]byte(nil), keyData[1:]...)
+		key.pubKey = privateToCompressed(key.key)
+	} else {
+		if _, _, err := secp256k1Decompress(keyData); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+		}
+		key.key = append([]byte(nil), keyData...)
+		key.pubKey = key.key
+	}
+
+	return key, nil
+}
+
+// Zero clears the key material held by the extended key
+func (k *ExtendedKey) Zero() {
+	if k == nil {
+		return
+	}
+	secureClear(k.key)
+	secureClear(k.chainCode)
+	k.key = nil
+	k.chainCode = nil
+	k.pubKey = nil
+}
+
+// privateToCompressed returns the compressed public key for a private key
+func privateToCompressed(key []byte) []byte {
+	x, y := secp256k1ScalarBaseMult(key)
+	return secp256k1Compress(x, y)
+}
+
+// clone returns a deep copy of the extended key
+func (k *ExtendedKey) clone() *ExtendedKey {
+	return &ExtendedKey{
+		key:       append([]byte(nil), k.key...),
+		pubKey:    append([]byte(nil), k.pubKey...),
+		chainCode: append([]byte(nil), k.chainCode...),
+		parentFP:  append([]byte(nil), k.parentFP...),
+		depth:     k.depth,
+		childNum:  k.childNum,
+		isPrivate: k.isPrivate,
+	}
+}
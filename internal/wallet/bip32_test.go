@@ -0,0 +1,257 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// BIP-32 test vector seeds from
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+const (
+	bip32Seed1 = "000102030405060708090a0b0c0d0e0f"
+	bip32Seed2 = "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542"
+	bip32Seed3 = "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be"
+	bip32Seed4 = "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678"
+)
+
+// bip32Vectors covers test vectors 1-4; vector 3 exercises retention of
+// leading zeros in private keys and vector 4 in hardened derivation.
+var bip32Vectors = []struct {
+	name string
+	seed string
+	path DerivationPath
+	xpub string
+	xprv string
+}{
+	{
+		name: "Vector 1 m",
+		seed: bip32Seed1,
+		path: DerivationPath{},
+		xpub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		xprv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+	},
+	{
+		name: "Vector 1 m/0H",
+		seed: bip32Seed1,
+		path: DerivationPath{HardenedKeyStart + 0},
+		xpub: "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		xprv: "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+	},
+	{
+		name: "Vector 1 m/0H/1",
+		seed: bip32Seed1,
+		path: DerivationPath{HardenedKeyStart + 0, 1},
+		xpub: "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		xprv: "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+	},
+	{
+		name: "Vector 1 m/0H/1/2H",
+		seed: bip32Seed1,
+		path: DerivationPath{HardenedKeyStart + 0, 1, HardenedKeyStart + 2},
+		xpub: "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+		xprv: "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+	},
+	{
+		name: "Vector 1 m/0H/1/2H/2",
+		seed: bip32Seed1,
+		path: DerivationPath{HardenedKeyStart + 0, 1, HardenedKeyStart + 2, 2},
+		xpub: "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+		xprv: "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+	},
+	{
+		name: "Vector 1 m/0H/1/2H/2/1000000000",
+		seed: bip32Seed1,
+		path: DerivationPath{HardenedKeyStart + 0, 1, HardenedKeyStart + 2, 2, 1000000000},
+		xpub: "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		xprv: "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+	},
+	{
+		name: "Vector 2 m",
+		seed: bip32Seed2,
+		path: DerivationPath{},
+		xpub: "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+		xprv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+	},
+	{
+		name: "Vector 2 m/0",
+		seed: bip32Seed2,
+		path: DerivationPath{0},
+		xpub: "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+		xprv: "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+	},
+	{
+		name: "Vector 2 m/0/2147483647H",
+		seed: bip32Seed2,
+		path: DerivationPath{0, HardenedKeyStart + 2147483647},
+		xpub: "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+		xprv: "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+	},
+	{
+		name: "Vector 2 m/0/2147483647H/1",
+		seed: bip32Seed2,
+		path: DerivationPath{0, HardenedKeyStart + 2147483647, 1},
+		xpub: "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+		xprv: "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+	},
+	{
+		name: "Vector 2 m/0/2147483647H/1/2147483646H",
+		seed: bip32Seed2,
+		path: DerivationPath{0, HardenedKeyStart + 2147483647, 1, HardenedKeyStart + 2147483646},
+		xpub: "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+		xprv: "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+	},
+	{
+		name: "Vector 2 m/0/2147483647H/1/2147483646H/2",
+		seed: bip32Seed2,
+		path: DerivationPath{0, HardenedKeyStart + 2147483647, 1, HardenedKeyStart + 2147483646, 2},
+		xpub: "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+		xprv: "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+	},
+	{
+		name: "Vector 3 m",
+		seed: bip32Seed3,
+		path: DerivationPath{},
+		xpub: "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+		xprv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+	},
+	{
+		name: "Vector 3 m/0H",
+		seed: bip32Seed3,
+		path: DerivationPath{HardenedKeyStart + 0},
+		xpub: "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+		xprv: "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+	},
+	{
+		name: "Vector 4 m",
+		seed: bip32Seed4,
+		path: DerivationPath{},
+		xpub: "xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+		xprv: "xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+	},
+	{
+		name: "Vector 4 m/0H",
+		seed: bip32Seed4,
+		path: DerivationPath{HardenedKeyStart + 0},
+		xpub: "xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+		xprv: "xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+	},
+	{
+		name: "Vector 4 m/0H/1H",
+		seed: bip32Seed4,
+		path: DerivationPath{HardenedKeyStart + 0, HardenedKeyStart + 1},
+		xpub: "xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+		xprv: "xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+	},
+}
+
+func TestBIP32Vectors(t *testing.T) {
+	for _, v := range bip32Vectors {
+		t.Run(v.name, func(t *testing.T) {
+			seed, _ := hex.DecodeString(v.seed)
+			master, err := NewMasterKey(seed)
+			if err != nil {
+				t.Fatalf("NewMasterKey() failed: %v", err)
+			}
+
+			key, err := master.DerivePath(v.path)
+			if err != nil {
+				t.Fatalf("DerivePath() failed: %v", err)
+			}
+
+			if got := key.String(); got != v.xprv {
+				t.Errorf("xprv = %s, want %s", got, v.xprv)
+			}
+			if got := key.Neuter().String(); got != v.xpub {
+				t.Errorf("xpub = %s, want %s", got, v.xpub)
+			}
+			if key.Depth() != uint8(len(v.path)) {
+				t.Errorf("Depth() = %d, want %d", key.Depth(), len(v.path))
+			}
+
+			// Serialized keys round-trip through the parser
+			for _, s := range []string{v.xprv, v.xpub} {
+				parsed, err := ParseExtendedKey(s)
+				if err != nil {
+					t.Errorf("ParseExtendedKey(%s) failed: %v", s, err)
+					continue
+				}
+				if parsed.String() != s {
+					t.Errorf("ParseExtendedKey(%s).String() = %s", s, parsed.String())
+				}
+			}
+		})
+	}
+}
+
+func TestBIP32PublicDerivation(t *testing.T) {
+	// Non-hardened children derived from the parent xpub match the
+	// neutered children derived from the parent xprv
+	parent, err := ParseExtendedKey("xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs")
+	if err != nil {
+		t.Fatalf("ParseExtendedKey() failed: %v", err)
+	}
+	public := parent.Neuter()
+
+	for _, index := range []uint32{0, 1, 2, 1000000000} {
+		privateChild, err := parent.Child(index)
+		if err != nil {
+			t.Fatalf("Child(%d) failed: %v", index, err)
+		}
+		publicChild, err := public.Child(index)
+		if err != nil {
+			t.Fatalf("public Child(%d) failed: %v", index, err)
+		}
+		if privateChild.Neuter().String() != publicChild.String() {
+			t.Errorf("Child(%d): public derivation %s, want %s", index, publicChild.String(), privateChild.Neuter().String())
+		}
+	}
+
+	if _, err := public.Child(HardenedKeyStart); !errors.Is(err, ErrHardenedFromPublic) {
+		t.Errorf("hardened Child() of public key = %v, want %v", err, ErrHardenedFromPublic)
+	}
+	if _, err := public.PrivateKeyBytes(); !errors.Is(err, ErrNotPrivate) {
+		t.Errorf("PrivateKeyBytes() of public key = %v, want %v", err, ErrNotPrivate)
+	}
+}
+
+func TestBIP32InvalidKeys(t *testing.T) {
+	// Test vector 5: invalid extended keys
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"pubkey version / prvkey mismatch", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm"},
+		{"prvkey version / pubkey mismatch", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH"},
+		{"invalid pubkey prefix 04", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn"},
+		{"invalid prvkey prefix 04", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ"},
+		{"invalid pubkey prefix 01", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4"},
+		{"invalid prvkey prefix 01", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J"},
+		{"zero depth with non-zero parent fingerprint", "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv"},
+		{"zero depth with non-zero parent fingerprint", "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ"},
+		{"zero depth with non-zero index", "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN"},
+		{"zero depth with non-zero index", "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8"},
+		{"unknown extended key version", "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4"},
+		{"unknown extended key version", "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9"},
+		{"private key 0 not in 1..n-1", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx"},
+		{"private key n not in 1..n-1", "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G"},
+		{"invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007", "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY"},
+		{"invalid checksum", "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseExtendedKey(tt.key); !errors.Is(err, ErrInvalidExtendedKey) {
+				t.Errorf("ParseExtendedKey() = %v, want %v", err, ErrInvalidExtendedKey)
+			}
+		})
+	}
+}
+
+func TestNewMasterKeySeedLength(t *testing.T) {
+	for _, n := range []int{0, 15, 65} {
+		if _, err := NewMasterKey(make([]byte, n)); !errors.Is(err, ErrInvalidSeed) {
+			t.Errorf("NewMasterKey(%d bytes) = %v, want %v", n, err, ErrInvalidSeed)
+		}
+	}
+}
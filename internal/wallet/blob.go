@@ -0,0 +1,168 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"simple-eth-hd-wallet/internal/rlp"
+)
+
+// EIP-4844 blob constants
+const (
+	// BlobLength is the size of a blob: 4096 field elements of 32 bytes
+	BlobLength = 131072
+	// KZGCommitmentLength is the size of a compressed BLS12-381 G1 point
+	KZGCommitmentLength = 48
+	// KZGProofLength is the size of a KZG proof
+	KZGProofLength = 48
+	// VersionedHashVersionKZG is the version byte of KZG versioned hashes
+	VersionedHashVersionKZG = 0x01
+	// CellsPerExtBlob is the number of cell proofs per blob in a version 1
+	// (EIP-7594) sidecar
+	CellsPerExtBlob = 128
+)
+
+// Blob sidecar versions
+const (
+	// BlobSidecarVersion0 carries one KZG proof per blob (EIP-4844)
+	BlobSidecarVersion0 = 0
+	// BlobSidecarVersion1 carries CellsPerExtBlob cell proofs per blob
+	// (EIP-7594)
+	BlobSidecarVersion1 = 1
+)
+
+// ErrInvalidSidecar is returned when a blob sidecar does not match the
+// versioned hashes of its transaction
+var ErrInvalidSidecar = errors.New("invalid blob sidecar")
+
+// Blob is the data carried by a blob transaction
+type Blob [BlobLength]byte
+
+// KZGCommitment is a KZG commitment to a blob
+type KZGCommitment [KZGCommitmentLength]byte
+
+// KZGProof is a KZG proof for a blob or a blob cell
+type KZGProof [KZGProofLength]byte
+
+// BlobSidecar holds the blobs of a blob transaction and the KZG material
+// proving them. It travels with the transaction in the network encoding but
+// is not part of the signed or canonical encoding. The commitments and proofs
+// are computed by the caller.
+type BlobSidecar struct {
+	Version     uint8
+	Blobs       []Blob
+	Commitments []KZGCommitment
+	Proofs      []KZGProof
+}
+
+// KZGToVersionedHash returns the versioned hash of a KZG commitment: the
+// version byte followed by the last 31 bytes of its SHA-256 digest
+func KZGToVersionedHash(commitment KZGCommitment) [HashLength]byte {
+	hash := sha256.Sum256(commitment[:])
+	hash[0] = VersionedHashVersionKZG
+	return hash
+}
+
+// BlobHashes returns the versioned hashes of the sidecar commitments, the
+// value for the BlobHashes field of the transaction
+func (s *BlobSidecar) BlobHashes() [][HashLength]byte {
+	hashes := make([][HashLength]byte, len(s.Commitments))
+	for i, commitment := range s.Commitments {
+		hashes[i] = KZGToVersionedHash(commitment)
+	}
+	return hashes
+}
+
+// validate checks that the sidecar holds one blob, commitment and set of
+// proofs for each versioned hash, and that the commitments match the hashes
+func (s *BlobSidecar) validate(hashes [][HashLength]byte) error {
+	if len(s.Blobs) != len(hashes) || len(s.Commitments) != len(hashes) {
+		return fmt.Errorf("%w: %d blobs and %d commitments for %d versioned hashes",
+			ErrInvalidSidecar, len(s.Blobs), len(s.Commitments), len(hashes))
+	}
+
+	proofs := len(hashes)
+	switch s.Version {
+	case BlobSidecarVersion0:
+	case BlobSidecarVersion1:
+		proofs *= CellsPerExtBlob
+	default:
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSidecar, s.Version)
+	}
+	if len(s.Proofs) != proofs {
+		return fmt.Errorf("%w: %d proofs, want %d", ErrInvalidSidecar, len(s.Proofs), proofs)
+	}
+
+	for i, commitment := range s.Commitments {
+		if KZGToVersionedHash(commitment) != hashes[i] {
+			return fmt.Errorf("%w: commitment %d does not match versioned hash", ErrInvalidSidecar, i)
+		}
+	}
+	return nil
+}
+
+// fields returns the sidecar items that follow the transaction in the
+// network wrapper. Version 0 has no version item.
+func (s *BlobSidecar) fields() []interface{} {
+	fields := []interface{}{s.Blobs, s.Commitments, s.Proofs}
+	if s.Version != BlobSidecarVersion0 {
+		fields = append([]interface{}{s.Version}, fields...)
+	}
+	return fields
+}
+
+// validateBlobs checks the fields specific to blob transactions
+func (tx *Transaction) validateBlobs() error {
+	if tx.To == nil {
+		return fmt.Errorf("%w: blob transaction without recipient", ErrInvalidTransaction)
+	}
+	if len(tx.BlobHashes) == 0 {
+		return fmt.Errorf("%w: blob transaction without blobs", ErrInvalidTransaction)
+	}
+	for i, hash := range tx.BlobHashes {
+		if hash[0] != VersionedHashVersionKZG {
+			return fmt.Errorf("%w: blob hash %d has version 0x%02x", ErrInvalidTransaction, i, hash[0])
+		}
+	}
+	if tx.Sidecar != nil {
+		return tx.Sidecar.validate(tx.BlobHashes)
+	}
+	return nil
+}
+
+// splitBlobWrapper separates the payload of a blob transaction in network
+// form into the transaction and its sidecar. A canonical payload is returned
+// unchanged with a nil sidecar.
+func splitBlobWrapper(payload []byte) ([]byte, *BlobSidecar, error) {
+	content, _, err := rlp.SplitList(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	kind, _, _, err := rlp.Split(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if kind != rlp.List {
+		return payload, nil, nil
+	}
+
+	count, err := rlp.CountValues(content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	var body rlp.RawValue
+	sidecar := new(BlobSidecar)
+	targets := []interface{}{&body, &sidecar.Blobs, &sidecar.Commitments, &sidecar.Proofs}
+	if count == 5 {
+		targets = []interface{}{&body, &sidecar.Version, &sidecar.Blobs, &sidecar.Commitments, &sidecar.Proofs}
+	}
+	if err := decodeFields(payload, targets); err != nil {
+		return nil, nil, err
+	}
+	if count == 5 && sidecar.Version == BlobSidecarVersion0 {
+		return nil, nil, fmt.Errorf("%w: explicit version 0", ErrInvalidSidecar)
+	}
+	return body, sidecar, nil
+}
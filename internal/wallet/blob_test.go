@@ -0,0 +1,221 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"simple-eth-hd-wallet/internal/rlp"
+)
+
+// The commitment and proof of the all-zero blob: the compressed point at
+// infinity
+var emptyBlobCommitment = KZGCommitment{0xc0}
+
+const emptyBlobHash = "010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014"
+
+// blobTxVector is a blob transaction signed with key 0x4646…46 in canonical
+// form
+const blobTxVector = "03f8920180843b9aca008506fc23ac00825208943535353535353535353535353535353535353535" +
+	"8080c0843b9aca00e1a0010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c44401480" +
+	"a09c2321f8926bf88713848f96514de60c18e27fedd70a7defda5dc6145451ec06" +
+	"a066f1ad53653020231e0c9f6f9ba678db068e616da31a50d5c304ec68beac5a5b"
+
+func newBlobTx(sidecar *BlobSidecar) *Transaction {
+	return &Transaction{
+		Type:       BlobTxType,
+		ChainID:    big.NewInt(1),
+		GasTipCap:  big.NewInt(1000000000),
+		GasFeeCap:  big.NewInt(30000000000),
+		Gas:        21000,
+		To:         addressPtr("0x3535353535353535353535353535353535353535"),
+		BlobFeeCap: big.NewInt(1000000000),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	}
+}
+
+func emptyBlobSidecar(version uint8) *BlobSidecar {
+	proofs := 1
+	if version == BlobSidecarVersion1 {
+		proofs = CellsPerExtBlob
+	}
+	return &BlobSidecar{
+		Version:     version,
+		Blobs:       make([]Blob, 1),
+		Commitments: []KZGCommitment{emptyBlobCommitment},
+		Proofs:      make([]KZGProof, proofs),
+	}
+}
+
+func TestKZGToVersionedHash(t *testing.T) {
+	hash := KZGToVersionedHash(emptyBlobCommitment)
+	if got := hex.EncodeToString(hash[:]); got != emptyBlobHash {
+		t.Errorf("KZGToVersionedHash = %s, want %s", got, emptyBlobHash)
+	}
+}
+
+func TestBlobTxVector(t *testing.T) {
+	tx := newBlobTx(emptyBlobSidecar(BlobSidecarVersion0))
+	tx.Sidecar = nil
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "2cd9b9d1643804245c897695fab258b7f0fb3755d39b1bc44b71bcf919720b5c" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	sig, err := signHash(mustBigInt("0x4646464646464646464646464646464646464646464646464646464646464646"), hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	if got := hex.EncodeToString(raw); got != blobTxVector {
+		t.Errorf("MarshalBinary = %s, want %s", got, blobTxVector)
+	}
+	txHash, _ := signed.Hash()
+	if got := hex.EncodeToString(txHash); got != "7d6cc315531ba7b2820a721c9e8417829075fbaac753acfb1005bee29b7bf2bd" {
+		t.Errorf("Hash = %s", got)
+	}
+
+	decoded, err := ParseRawTransaction(blobTxVector)
+	if err != nil {
+		t.Fatalf("ParseRawTransaction failed: %v", err)
+	}
+	if decoded.Sidecar != nil || decoded.BlobFeeCap.Cmp(tx.BlobFeeCap) != 0 || len(decoded.BlobHashes) != 1 ||
+		hex.EncodeToString(decoded.BlobHashes[0][:]) != emptyBlobHash {
+		t.Errorf("Decoded %+v", decoded)
+	}
+	sender, err := decoded.Sender()
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if want := HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"); sender != want {
+		t.Errorf("Sender = %s, want %s", sender.Hex(), want.Hex())
+	}
+}
+
+func TestBlobTxNetworkForm(t *testing.T) {
+	wallet, account := newTestWallet(t)
+
+	for _, version := range []uint8{BlobSidecarVersion0, BlobSidecarVersion1} {
+		tx := newBlobTx(emptyBlobSidecar(version))
+		signed, err := wallet.SignTransaction(account.Address, tx)
+		if err != nil {
+			t.Fatalf("SignTransaction failed: %v", err)
+		}
+
+		network, err := signed.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		canonical, err := signed.CanonicalBinary()
+		if err != nil {
+			t.Fatalf("CanonicalBinary failed: %v", err)
+		}
+		if network[0] != BlobTxType || canonical[0] != BlobTxType {
+			t.Fatalf("Encodings start with %#x and %#x", network[0], canonical[0])
+		}
+
+		// The wrapper holds the canonical payload followed by the sidecar
+		var items []rlp.RawValue
+		if err := rlp.DecodeBytes(network[1:], &items); err != nil {
+			t.Fatalf("Network form is not an RLP list: %v", err)
+		}
+		if want := 4 + int(version); len(items) != want {
+			t.Errorf("Version %d wrapper has %d items, want %d", version, len(items), want)
+		}
+		if !bytes.Equal(items[0], canonical[1:]) {
+			t.Error("Wrapper does not start with the canonical payload")
+		}
+
+		// The sidecar does not change the signing hash or transaction hash
+		withoutSidecar := *tx
+		withoutSidecar.Sidecar = nil
+		hash, _ := tx.SigningHash()
+		plainHash, _ := withoutSidecar.SigningHash()
+		if !bytes.Equal(hash, plainHash) {
+			t.Error("Sidecar changed the signing hash")
+		}
+		txHash, _ := signed.Hash()
+		if !bytes.Equal(txHash, keccak256(canonical)) {
+			t.Error("Hash is not keccak256 of the canonical encoding")
+		}
+
+		// Both forms decode; only the network form keeps the sidecar
+		decoded := new(Transaction)
+		if err := decoded.UnmarshalBinary(network); err != nil {
+			t.Fatalf("UnmarshalBinary(network) failed: %v", err)
+		}
+		if decoded.Sidecar == nil || decoded.Sidecar.Version != version || len(decoded.Sidecar.Proofs) != len(tx.Sidecar.Proofs) ||
+			decoded.Sidecar.Commitments[0] != emptyBlobCommitment {
+			t.Errorf("Decoded sidecar %+v", decoded.Sidecar)
+		}
+		again, _ := decoded.MarshalBinary()
+		if !bytes.Equal(again, network) {
+			t.Error("Network form does not round trip")
+		}
+		if sender, err := decoded.Sender(); err != nil || sender != account.Address {
+			t.Errorf("Sender = %s, %v; want %s", sender.Hex(), err, account.Address.Hex())
+		}
+
+		if err := decoded.UnmarshalBinary(canonical); err != nil {
+			t.Fatalf("UnmarshalBinary(canonical) failed: %v", err)
+		}
+		if decoded.Sidecar != nil {
+			t.Error("Canonical form decoded with a sidecar")
+		}
+	}
+}
+
+func TestBlobTxErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(tx *Transaction)
+		err    error
+	}{
+		{"contract creation", func(tx *Transaction) { tx.To = nil }, ErrInvalidTransaction},
+		{"no blobs", func(tx *Transaction) { tx.BlobHashes, tx.Sidecar = nil, nil }, ErrInvalidTransaction},
+		{"hash version", func(tx *Transaction) { tx.BlobHashes[0][0] = 0x02 }, ErrInvalidTransaction},
+		{"gas price", func(tx *Transaction) { tx.GasPrice = big.NewInt(1) }, ErrInvalidTransaction},
+		{"negative blob fee", func(tx *Transaction) { tx.BlobFeeCap = big.NewInt(-1) }, ErrInvalidTransaction},
+		{"blob fields on type 2", func(tx *Transaction) { tx.Type = DynamicFeeTxType }, ErrInvalidTransaction},
+		{"commitment mismatch", func(tx *Transaction) { tx.Sidecar.Commitments[0][1] = 1 }, ErrInvalidSidecar},
+		{"missing blob", func(tx *Transaction) { tx.Sidecar.Blobs = nil }, ErrInvalidSidecar},
+		{"proof count", func(tx *Transaction) { tx.Sidecar.Version = BlobSidecarVersion1 }, ErrInvalidSidecar},
+		{"sidecar version", func(tx *Transaction) { tx.Sidecar.Version = 2 }, ErrInvalidSidecar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newBlobTx(emptyBlobSidecar(BlobSidecarVersion0))
+			tt.modify(tx)
+			if _, err := tx.SigningHash(); !errors.Is(err, tt.err) {
+				t.Errorf("SigningHash error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	// A version 1 wrapper must not declare version 0
+	wallet, account := newTestWallet(t)
+	signed, err := wallet.SignTransaction(account.Address, newBlobTx(emptyBlobSidecar(BlobSidecarVersion1)))
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+	signed.Sidecar.Version = BlobSidecarVersion0
+	fields, _ := signed.signedFields()
+	raw, _ := signed.encode([]interface{}{fields, uint8(0), signed.Sidecar.Blobs, signed.Sidecar.Commitments, signed.Sidecar.Proofs})
+	if err := new(Transaction).UnmarshalBinary(raw); !errors.Is(err, ErrInvalidSidecar) {
+		t.Errorf("Expected ErrInvalidSidecar, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+//go:build ignore
+
+// gen_nfkd generates nfkd_tables.go from the Unicode Character Database.
+//
+// Usage:
+//
+//	go run gen_nfkd.go [-version 14.0.0] [-ucd UnicodeData.txt] [-sha256 hex] [-out nfkd_tables.go]
+//
+// Without -ucd the UnicodeData.txt of -version is downloaded from unicode.org.
+// With -sha256 the input must have that SHA-256 digest. The digest of the
+// input is always printed so that it can be recorded alongside a regeneration.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Hangul syllables are decomposed algorithmically by normalizeNFKD
+const (
+	hangulFirst = 0xAC00
+	hangulLast  = 0xD7A3
+)
+
+func main() {
+	version := flag.String("version", "14.0.0", "Unicode version")
+	ucd := flag.String("ucd", "", "path of UnicodeData.txt (default: download)")
+	digest := flag.String("sha256", "", "expected SHA-256 of UnicodeData.txt")
+	out := flag.String("out", "nfkd_tables.go", "output file")
+	flag.Parse()
+
+	data, err := readUCD(*ucd, *version)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	log.Printf("UnicodeData.txt SHA-256: %s", hex.EncodeToString(sum[:]))
+	if *digest != "" && !strings.EqualFold(*digest, hex.EncodeToString(sum[:])) {
+		log.Fatalf("UnicodeData.txt SHA-256 does not match %s", *digest)
+	}
+
+	decompositions, classes, err := parseUCD(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := format.Source(generate(*version, decompositions, classes))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readUCD reads UnicodeData.txt from path, or downloads it for version
+func readUCD(path, version string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+
+	url := "https://www.unicode.org/Public/" + version + "/ucd/UnicodeData.txt"
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parseUCD returns the full compatibility decomposition of every code point
+// that has one, and every non-zero canonical combining class
+func parseUCD(data []byte) (map[rune][]rune, map[rune]uint8, error) {
+	mappings := make(map[rune][]rune)
+	classes := make(map[rune]uint8)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 6 {
+			return nil, nil, fmt.Errorf("malformed line %q", line)
+		}
+		code, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("malformed code point in %q", line)
+		}
+		r := rune(code)
+
+		class, err := strconv.ParseUint(fields[3], 10, 8)
+		if err != nil {
+			return nil, nil, fmt.Errorf("malformed combining class in %q", line)
+		}
+		if class != 0 {
+			classes[r] = uint8(class)
+		}
+
+		// Both canonical and <tag>ged compatibility mappings apply to NFKD
+		decomposition := fields[5]
+		if strings.HasPrefix(decomposition, "<") {
+			decomposition = decomposition[strings.Index(decomposition, ">")+1:]
+		}
+		if decomposition = strings.TrimSpace(decomposition); decomposition == "" {
+			continue
+		}
+		for _, part := range strings.Fields(decomposition) {
+			c, err := strconv.ParseUint(part, 16, 32)
+			if err != nil {
+				return nil, nil, fmt.Errorf("malformed decomposition in %q", line)
+			}
+			mappings[r] = append(mappings[r], rune(c))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	decompositions := make(map[rune][]rune, len(mappings))
+	for r := range mappings {
+		if r >= hangulFirst && r <= hangulLast {
+			continue
+		}
+		decompositions[r] = expand(r, mappings)
+	}
+	return decompositions, classes, nil
+}
+
+// expand applies the decomposition mappings recursively
+func expand(r rune, mappings map[rune][]rune) []rune {
+	mapping, exists := mappings[r]
+	if !exists {
+		return []rune{r}
+	}
+	var full []rune
+	for _, c := range mapping {
+		full = append(full, expand(c, mappings)...)
+	}
+	return full
+}
+
+// generate writes the Go source of the tables
+func generate(version string, decompositions map[rune][]rune, classes map[rune]uint8) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_nfkd.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package wallet\n\n")
+	fmt.Fprintf(&b, "// Unicode compatibility decomposition data used by normalizeNFKD,\n")
+	fmt.Fprintf(&b, "// from the Unicode Character Database version %s. Regenerate with go generate.\n\n", version)
+
+	fmt.Fprintf(&b, "// nfkdDecompositions maps each code point to its full compatibility\n")
+	fmt.Fprintf(&b, "// decomposition. Hangul syllables are decomposed algorithmically and are\n")
+	fmt.Fprintf(&b, "// therefore not listed.\n")
+	fmt.Fprintf(&b, "var nfkdDecompositions = map[rune]string{\n")
+	for _, r := range sortedKeys(decompositions) {
+		fmt.Fprintf(&b, "\t0x%04X: %s,\n", r, quote(decompositions[r]))
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// combiningClasses holds the non-zero canonical combining classes used for\n")
+	fmt.Fprintf(&b, "// canonical reordering of combining marks.\n")
+	fmt.Fprintf(&b, "var combiningClasses = map[rune]uint8{\n")
+	for _, r := range sortedKeys(classes) {
+		fmt.Fprintf(&b, "\t0x%04X: %d,\n", r, classes[r])
+	}
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+// sortedKeys returns the keys of m in ascending order
+func sortedKeys[V any](m map[rune]V) []rune {
+	keys := make([]rune, 0, len(m))
+	for r := range m {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// quote writes runes as a Go string literal, escaping everything outside
+// printable ASCII with upper-case \u or \U escapes
+func quote(runes []rune) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range runes {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			b.WriteRune(r)
+		case r <= 0xFFFF:
+			fmt.Fprintf(&b, "\\u%04X", r)
+		default:
+			fmt.Fprintf(&b, "\\U%08X", r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
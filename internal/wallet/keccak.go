@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Ethereum hashes with the original Keccak submission, which pads with 0x01
+// rather than the 0x06 domain byte of the standardized SHA3-256, so the
+// standard library's crypto/sha3 (where available) cannot be used.
+
+const (
+	// keccak256Size is the digest size of Keccak-256 in bytes
+	keccak256Size = 32
+	// keccak256Rate is the sponge rate of Keccak-256 in bytes (1600 - 2·256 bits)
+	keccak256Rate = 136
+)
+
+// keccakRoundConstants are the iota step constants of Keccak-f[1600]
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations are the rho step offsets, indexed by x + 5y
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccakF1600 applies the Keccak-f[1600] permutation to the state
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	var b [25]uint64
+	for round := 0; round < 24; round++ {
+		// θ
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[x+y] ^= d
+			}
+		}
+
+		// ρ and π
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+
+		// χ
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[x+y] = b[x+y] ^ (^b[(x+1)%5+y] & b[(x+2)%5+y])
+			}
+		}
+
+		// ι
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
+
+// keccakState is a Keccak-256 sponge implementing hash.Hash
+type keccakState struct {
+	a   [25]uint64
+	buf [keccak256Rate]byte
+	n   int
+}
+
+// newKeccak256 returns a new legacy Keccak-256 hash
+func newKeccak256() hash.Hash {
+	return &keccakState{}
+}
+
+// keccak256 returns the Keccak-256 digest of the concatenated inputs
+func keccak256(data ...[]byte) []byte {
+	h := newKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// absorb XORs a full block into the state and permutes it
+func (k *keccakState) absorb(block []byte) {
+	for i := 0; i < keccak256Rate/8; i++ {
+		k.a[i] ^= binary.LittleEndian.Uint64(block[8*i:])
+	}
+	keccakF1600(&k.a)
+}
+
+// Write absorbs more data into the hash state
+func (k *keccakState) Write(p []byte) (int, error) {
+	written := len(p)
+	if k.n > 0 {
+		copied := copy(k.buf[k.n:], p)
+		k.n += copied
+		p = p[copied:]
+		if k.n < keccak256Rate {
+			return written, nil
+		}
+		k.absorb(k.buf[:])
+		k.n = 0
+	}
+	for len(p) >= keccak256Rate {
+		k.absorb(p[:keccak256Rate])
+		p = p[keccak256Rate:]
+	}
+	k.n = copy(k.buf[:], p)
+	return written, nil
+}
+
+// Sum appends the digest to b without changing the hash state
+func (k *keccakState) Sum(b []byte) []byte {
+	final := *k
+
+	// Keccak padding: 0x01 after the message, 0x80 in the last byte of the block
+	for i := final.n; i < keccak256Rate; i++ {
+		final.buf[i] = 0
+	}
+	final.buf[final.n] ^= 0x01
+	final.buf[keccak256Rate-1] ^= 0x80
+	final.absorb(final.buf[:])
+
+	var digest [keccak256Size]byte
+	for i := 0; i < keccak256Size/8; i++ {
+		binary.LittleEndian.PutUint64(digest[8*i:], final.a[i])
+	}
+	return append(b, digest[:]...)
+}
+
+// Reset resets the hash to its initial state
+func (k *keccakState) Reset() {
+	*k = keccakState{}
+}
+
+// Size returns the digest size in bytes
+func (k *keccakState) Size() int {
+	return keccak256Size
+}
+
+// BlockSize returns the sponge rate in bytes
+func (k *keccakState) BlockSize() int {
+	return keccak256Rate
+}
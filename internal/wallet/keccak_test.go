@@ -0,0 +1,54 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+		// Inputs around the 136-byte rate exercise the padding boundaries
+		{strings.Repeat("a", 135), "34367dc248bbd832f4e3e69dfaac2f92638bd0bbd18f2912ba4ef454919cf446"},
+		{strings.Repeat("a", 136), "a6c4d403279fe3e0af03729caada8374b5ca54d8065329a3ebcaeb4b60aa386e"},
+		{strings.Repeat("a", 137), "d869f639c7046b4929fc92a4d988a8b22c55fbadb802c0c66ebcd484f1915f39"},
+		{strings.Repeat("a", 1000000), "fadae6b49f129bbb812be8407b7b2894f34aecf6dbd1f9b0f0c7e9853098fc96"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(keccak256([]byte(tt.input))); got != tt.expected {
+			t.Errorf("keccak256(%d bytes) = %s, want %s", len(tt.input), got, tt.expected)
+		}
+
+		// Streaming in uneven chunks gives the same digest
+		h := newKeccak256()
+		data := []byte(tt.input)
+		for len(data) > 0 {
+			n := 7
+			if n > len(data) {
+				n = len(data)
+			}
+			h.Write(data[:n])
+			data = data[n:]
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != tt.expected {
+			t.Errorf("streamed keccak256(%d bytes) = %s, want %s", len(tt.input), got, tt.expected)
+		}
+	}
+}
+
+func TestKeccak256SumDoesNotModifyState(t *testing.T) {
+	h := newKeccak256()
+	h.Write([]byte("ab"))
+	h.Sum(nil)
+	h.Write([]byte("c"))
+	if got := hex.EncodeToString(h.Sum(nil)); got != "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45" {
+		t.Errorf("keccak256 after intermediate Sum = %s", got)
+	}
+}
@@ -0,0 +1,375 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Web3 Secret Storage (keystore v3) constants
+const (
+	// KeystoreVersion is the supported keystore format version
+	KeystoreVersion = 3
+	// KDFScrypt and KDFPBKDF2 name the keystore key derivation functions
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+
+	keystoreCipher    = "aes-128-ctr"
+	keystorePRF       = "hmac-sha256"
+	keystoreKeyLength = 32
+	keystoreSaltLen   = 32
+
+	// keystoreMaxMemory bounds the scrypt memory a keystore may demand
+	keystoreMaxMemory = 1 << 30
+	// keystoreMaxWork bounds the scrypt cost N*r*p a keystore may demand,
+	// 32 times that of StandardKeystoreParams
+	keystoreMaxWork = 1 << 26
+	// keystoreMaxIterations bounds the PBKDF2 cost a keystore may demand
+	keystoreMaxIterations = 1 << 24
+)
+
+// ErrInvalidKeystore is returned for keystore files that are malformed or use
+// unsupported parameters
+var ErrInvalidKeystore = errors.New("invalid keystore")
+
+// KeystoreParams selects the key derivation function of an encrypted
+// keystore
+type KeystoreParams struct {
+	// KDF is KDFScrypt or KDFPBKDF2
+	KDF string
+	// ScryptN and ScryptP are the scrypt cost parameters; r is always 8
+	ScryptN int
+	ScryptP int
+	// Iterations is the PBKDF2-HMAC-SHA256 iteration count
+	Iterations int
+}
+
+// Keystore parameter presets. StandardKeystoreParams matches geth's default
+// and takes about a second and 256 MiB to decrypt; LightKeystoreParams suits
+// constrained devices and tests.
+var (
+	StandardKeystoreParams = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 18, ScryptP: 1}
+	LightKeystoreParams    = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 12, ScryptP: 6}
+	PBKDF2KeystoreParams   = KeystoreParams{KDF: KDFPBKDF2, Iterations: 1 << 18}
+)
+
+// keystoreJSON is the keystore v3 file layout
+type keystoreJSON struct {
+	Address string         `json:"address,omitempty"`
+	Crypto  keystoreCrypto `json:"crypto"`
+	ID      string         `json:"id"`
+	Version int            `json:"version"`
+}
+
+// keystoreCrypto is the crypto section of a keystore. Older geth files
+// spell it "Crypto", which encoding/json matches as well.
+type keystoreCrypto struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   keystoreHex            `json:"ciphertext"`
+	CipherParams keystoreCipherParams   `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          keystoreHex            `json:"mac"`
+}
+
+type keystoreCipherParams struct {
+	IV keystoreHex `json:"iv"`
+}
+
+// keystoreHex is a byte string written as unprefixed hex, as keystore files
+// store it
+type keystoreHex []byte
+
+// MarshalText implements encoding.TextMarshaler
+func (b keystoreHex) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *keystoreHex) UnmarshalText(text []byte) error {
+	return (*hexBytes)(b).UnmarshalText(text)
+}
+
+// EncryptKeystore encrypts a private key into a keystore v3 JSON file
+// protected by passphrase
+func EncryptKeystore(key *ecdsa.PrivateKey, passphrase string, params KeystoreParams) ([]byte, error) {
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	var kdfParams map[string]interface{}
+	switch params.KDF {
+	case KDFScrypt:
+		kdfParams = map[string]interface{}{
+			"n":     params.ScryptN,
+			"r":     8,
+			"p":     params.ScryptP,
+			"dklen": keystoreKeyLength,
+			"salt":  hex.EncodeToString(salt),
+		}
+	case KDFPBKDF2:
+		kdfParams = map[string]interface{}{
+			"c":     params.Iterations,
+			"prf":   keystorePRF,
+			"dklen": keystoreKeyLength,
+			"salt":  hex.EncodeToString(salt),
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported kdf %q", ErrInvalidKeystore, params.KDF)
+	}
+
+	derivedKey, err := keystoreKey([]byte(passphrase), params.KDF, kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	defer secureClear(derivedKey)
+
+	keyBytes := key.D.FillBytes(make([]byte, 32))
+	defer secureClear(keyBytes)
+	cipherText, err := aesCTR(derivedKey[:16], iv, keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	address := PubkeyToAddress(&key.PublicKey)
+	return json.MarshalIndent(keystoreJSON{
+		Address: hex.EncodeToString(address[:]),
+		Crypto: keystoreCrypto{
+			Cipher:       keystoreCipher,
+			CipherText:   cipherText,
+			CipherParams: keystoreCipherParams{IV: iv},
+			KDF:          params.KDF,
+			KDFParams:    kdfParams,
+			MAC:          keccak256(derivedKey[16:32], cipherText),
+		},
+		ID:      id,
+		Version: KeystoreVersion,
+	}, "", "  ")
+}
+
+// DecryptKeystore decrypts a keystore v3 JSON file. It returns
+// ErrInvalidPassphrase if the MAC does not match, and ErrInvalidKeystore if
+// the file is malformed or its address does not match the decrypted key.
+func DecryptKeystore(data []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrInvalidKeystore, ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("%w: unsupported cipher %q", ErrInvalidKeystore, ks.Crypto.Cipher)
+	}
+	if len(ks.Crypto.CipherParams.IV) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: IV must be %d bytes", ErrInvalidKeystore, aes.BlockSize)
+	}
+
+	derivedKey, err := keystoreKey([]byte(passphrase), ks.Crypto.KDF, ks.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	defer secureClear(derivedKey)
+
+	mac := keccak256(derivedKey[16:32], ks.Crypto.CipherText)
+	if !hmac.Equal(mac, ks.Crypto.MAC) {
+		return nil, ErrInvalidPassphrase
+	}
+
+	keyBytes, err := aesCTR(derivedKey[:16], ks.Crypto.CipherParams.IV, ks.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+	defer secureClear(keyBytes)
+
+	key, err := privateKeyFromBytes(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+	}
+	if ks.Address != "" {
+		address := PubkeyToAddress(&key.PublicKey)
+		if !strings.EqualFold(strings.TrimPrefix(ks.Address, "0x"), hex.EncodeToString(address[:])) {
+			secureClearPrivateKey(key)
+			return nil, fmt.Errorf("%w: key is for %s, not %s", ErrInvalidKeystore, address.Hex(), ks.Address)
+		}
+	}
+	return key, nil
+}
+
+// ExportKeystore encrypts the private key of a derived account into a
+// keystore v3 JSON file. It returns ErrWalletLocked if the wallet is locked.
+func (w *SimpleWallet) ExportKeystore(address Address, passphrase string, params KeystoreParams) ([]byte, error) {
+	key, err := w.copyPrivateKey(address)
+	if err != nil {
+		return nil, err
+	}
+	defer secureClearPrivateKey(key)
+
+	// Encrypt without w.mu so that the KDF does not hold up Lock
+	return EncryptKeystore(key, passphrase, params)
+}
+
+// copyPrivateKey returns a copy of the private key of a derived account that
+// Lock does not clear; the caller must clear it
+func (w *SimpleWallet) copyPrivateKey(address Address) (*ecdsa.PrivateKey, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.isLocked {
+		return nil, ErrWalletLocked
+	}
+	account, exists := w.accounts[address]
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	w.touch()
+
+	key := *account.PrivateKey
+	key.D = new(big.Int).Set(account.PrivateKey.D)
+	return &key, nil
+}
+
+// keystoreKey derives the 32-byte keystore key with the named KDF
+func keystoreKey(passphrase []byte, kdf string, params map[string]interface{}) ([]byte, error) {
+	salt, err := kdfParamBytes(params, "salt")
+	if err != nil {
+		return nil, err
+	}
+	dkLen, err := kdfParamInt(params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	if dkLen < keystoreKeyLength || dkLen > 2*keystoreKeyLength {
+		return nil, fmt.Errorf("%w: dklen %d", ErrInvalidKeystore, dkLen)
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		n, err := kdfParamInt(params, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := kdfParamInt(params, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := kdfParamInt(params, "p")
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 || r <= 0 || p <= 0 {
+			return nil, fmt.Errorf("%w: scrypt N=%d r=%d p=%d", ErrInvalidKeystore, n, r, p)
+		}
+		if n > keystoreMaxMemory/128/r || p > keystoreMaxMemory/128/r-n {
+			return nil, fmt.Errorf("%w: scrypt N=%d r=%d p=%d needs more than %d bytes", ErrInvalidKeystore, n, r, p, keystoreMaxMemory)
+		}
+		if p > keystoreMaxWork/n/r {
+			return nil, fmt.Errorf("%w: scrypt N=%d r=%d p=%d exceeds the work limit", ErrInvalidKeystore, n, r, p)
+		}
+		key, err := scryptKey(passphrase, salt, n, r, p, dkLen)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKeystore, err)
+		}
+		return key, nil
+
+	case KDFPBKDF2:
+		if prf, _ := params["prf"].(string); prf != keystorePRF {
+			return nil, fmt.Errorf("%w: unsupported prf %q", ErrInvalidKeystore, params["prf"])
+		}
+		c, err := kdfParamInt(params, "c")
+		if err != nil {
+			return nil, err
+		}
+		if c <= 0 || c > keystoreMaxIterations {
+			return nil, fmt.Errorf("%w: pbkdf2 iteration count %d", ErrInvalidKeystore, c)
+		}
+		return pbkdf2Key(passphrase, salt, c, dkLen, sha256.New), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported kdf %q", ErrInvalidKeystore, kdf)
+	}
+}
+
+// kdfParamInt reads an integer KDF parameter
+func kdfParamInt(params map[string]interface{}, name string) (int, error) {
+	switch v := params[name].(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: kdfparams.%s must be an integer", ErrInvalidKeystore, name)
+}
+
+// kdfParamBytes reads a hex KDF parameter
+func kdfParamBytes(params map[string]interface{}, name string) ([]byte, error) {
+	s, ok := params[name].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: kdfparams.%s must be a hex string", ErrInvalidKeystore, name)
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: kdfparams.%s: %v", ErrInvalidKeystore, name, err)
+	}
+	return b, nil
+}
+
+// aesCTR encrypts or decrypts data with AES in counter mode
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// privateKeyFromBytes builds a secp256k1 private key from a 32-byte scalar,
+// which must be in [1, n-1]
+func privateKeyFromBytes(b []byte) (*ecdsa.PrivateKey, error) {
+	if len(b) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes, got %d", len(b))
+	}
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() == 0 || d.Cmp(Secp256k1().Params().N) >= 0 {
+		return nil, errors.New("private key is out of range")
+	}
+
+	key := new(ecdsa.PrivateKey)
+	key.PublicKey.Curve = Secp256k1()
+	key.D = d
+	key.PublicKey.X, key.PublicKey.Y = Secp256k1().ScalarBaseMult(b)
+	return key, nil
+}
+
+// newUUID returns a random (version 4) UUID string
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", fmt.Errorf("failed to generate UUID: %w", err)
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
@@ -0,0 +1,143 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Web3 Secret Storage test vectors, password "testpassword"
+const (
+	keystorePBKDF2Vector = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	keystoreScryptVector = `{"Crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+	keystoreVectorKey    = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	keystoreVectorAddr   = "0x008AeEda4D805471dF9b2A5B0f38A0C3bCBA786b"
+)
+
+// testKeystoreParams keeps round-trip tests fast
+var testKeystoreParams = KeystoreParams{KDF: KDFScrypt, ScryptN: 1 << 10, ScryptP: 1}
+
+func TestDecryptKeystoreVectors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"PBKDF2", keystorePBKDF2Vector},
+		// The scrypt vector spells the crypto section as older geth did
+		{"Scrypt", keystoreScryptVector},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := DecryptKeystore([]byte(tt.json), "testpassword")
+			if err != nil {
+				t.Fatalf("DecryptKeystore failed: %v", err)
+			}
+			if got := hex.EncodeToString(key.D.FillBytes(make([]byte, 32))); got != keystoreVectorKey {
+				t.Errorf("Key = %s, want %s", got, keystoreVectorKey)
+			}
+			if got := PubkeyToAddress(&key.PublicKey).Hex(); got != keystoreVectorAddr {
+				t.Errorf("Address = %s, want %s", got, keystoreVectorAddr)
+			}
+
+			if _, err := DecryptKeystore([]byte(tt.json), "wrongpassword"); err != ErrInvalidPassphrase {
+				t.Errorf("Wrong password error = %v, want ErrInvalidPassphrase", err)
+			}
+		})
+	}
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, &WalletConfig{LockPassphrase: "lock secret"})
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	for _, params := range []KeystoreParams{
+		testKeystoreParams,
+		{KDF: KDFPBKDF2, Iterations: 1000},
+	} {
+		t.Run(params.KDF, func(t *testing.T) {
+			data, err := wallet.ExportKeystore(account.Address, "secret", params)
+			if err != nil {
+				t.Fatalf("ExportKeystore failed: %v", err)
+			}
+
+			var ks map[string]interface{}
+			if err := json.Unmarshal(data, &ks); err != nil {
+				t.Fatalf("Keystore is not JSON: %v", err)
+			}
+			if ks["version"] != float64(3) || ks["address"] != strings.ToLower(account.Address.Hex()[2:]) {
+				t.Errorf("Keystore header = %v %v", ks["version"], ks["address"])
+			}
+			if id, _ := ks["id"].(string); len(id) != 36 || id[14] != '4' {
+				t.Errorf("Keystore id %q is not a version 4 UUID", ks["id"])
+			}
+			if strings.Contains(string(data), keystoreHexKey(account)) {
+				t.Error("Keystore contains the plaintext key")
+			}
+
+			key, err := DecryptKeystore(data, "secret")
+			if err != nil {
+				t.Fatalf("DecryptKeystore failed: %v", err)
+			}
+			if key.D.Cmp(account.PrivateKey.D) != 0 || PubkeyToAddress(&key.PublicKey) != account.Address {
+				t.Error("Decrypted key does not match the account")
+			}
+		})
+	}
+
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if _, err := wallet.ExportKeystore(account.Address, "secret", testKeystoreParams); err != ErrWalletLocked {
+		t.Errorf("Locked export error = %v, want ErrWalletLocked", err)
+	}
+}
+
+// keystoreHexKey returns the hex private key of an account
+func keystoreHexKey(account *Account) string {
+	return hex.EncodeToString(account.PrivateKey.D.FillBytes(make([]byte, 32)))
+}
+
+func TestDecryptKeystoreErrors(t *testing.T) {
+	replace := func(old, new string) string {
+		return strings.Replace(keystorePBKDF2Vector, old, new, 1)
+	}
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"Not JSON", "{"},
+		{"Version 2", replace(`"version":3`, `"version":2`)},
+		{"Unknown cipher", replace(`"aes-128-ctr"`, `"aes-128-cbc"`)},
+		{"Short IV", replace(`"iv":"6087dab2f9fdbbfaddc31a909735c1e6"`, `"iv":"6087"`)},
+		{"Unknown KDF", replace(`"kdf":"pbkdf2"`, `"kdf":"argon2"`)},
+		{"Unknown PRF", replace(`"hmac-sha256"`, `"hmac-sha512"`)},
+		{"Short dklen", replace(`"dklen":32`, `"dklen":16`)},
+		{"Fractional count", replace(`"c":262144`, `"c":1.5`)},
+		{"Excessive count", replace(`"c":262144`, `"c":1000000000`)},
+		{"Bad salt", replace(`"salt":"ae3c`, `"salt":"zz3c`)},
+		{"Wrong address", replace(`"crypto"`, `"address":"0000000000000000000000000000000000000000","crypto"`)},
+		{"Excessive scrypt memory", strings.Replace(keystoreScryptVector, `"n":262144,"r":1`, `"n":2097152,"r":8`, 1)},
+		{"Excessive scrypt p memory", strings.Replace(keystoreScryptVector, `"p":8`, `"p":1073741823`, 1)},
+		{"Excessive scrypt work", strings.Replace(keystoreScryptVector, `"p":8`, `"p":1024`, 1)},
+		{"Zero scrypt p", strings.Replace(keystoreScryptVector, `"p":8`, `"p":0`, 1)},
+		{"Invalid scrypt N", strings.Replace(keystoreScryptVector, `"n":262144`, `"n":1000`, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptKeystore([]byte(tt.json), "testpassword"); !errors.Is(err, ErrInvalidKeystore) {
+				t.Errorf("DecryptKeystore error = %v, want ErrInvalidKeystore", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lock encryption parameters
+const (
+	// lockIterations is the PBKDF2-HMAC-SHA256 iteration count for the key
+	// that seals a locked wallet
+	lockIterations = 600000
+	// lockSaltLength is the length of the random PBKDF2 salt
+	lockSaltLength = 16
+)
+
+// ErrNoLockPassphrase is returned when locking a wallet created without
+// WalletConfig.LockPassphrase
+var ErrNoLockPassphrase = errors.New("wallet has no lock passphrase")
+
+// LockReason says why a wallet was locked
+type LockReason string
+
+// Lock reasons
+const (
+	// LockManual is a call to Lock
+	LockManual LockReason = "manual"
+	// LockIdle is an auto-lock after WalletConfig.AutoLockIdle without use
+	LockIdle LockReason = "idle"
+	// LockExpired is an auto-lock WalletConfig.AutoLockMax after the wallet
+	// was created or unlocked
+	LockExpired LockReason = "expired"
+)
+
+// LockEvent reports a change of the lock state to WalletConfig.OnLockEvent
+type LockEvent struct {
+	Locked bool
+	// Reason says why the wallet locked; it is empty for unlocks
+	Reason LockReason
+	Time   time.Time
+}
+
+// sealedSecrets is the AES-256-GCM encrypted seed and mnemonic of a wallet,
+// kept so that a locked wallet can be unlocked
+type sealedSecrets struct {
+	salt       []byte
+	nonce      []byte
+	ciphertext []byte
+}
+
+// lockAEAD returns the AES-256-GCM cipher keyed by PBKDF2 of passphrase
+func lockAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	key := pbkdf2Key(passphrase, salt, lockIterations, 32, sha256.New)
+	defer secureClear(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSecrets encrypts the seed and mnemonic under the lock passphrase
+func sealSecrets(passphrase string, seed []byte, mnemonic string) (*sealedSecrets, error) {
+	salt := make([]byte, lockSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	password := []byte(passphrase)
+	defer secureClear(password)
+	aead, err := lockAEAD(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	plaintext := append(append([]byte(nil), seed...), mnemonic...)
+	defer secureClear(plaintext)
+
+	return &sealedSecrets{
+		salt:       salt,
+		nonce:      nonce,
+		ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	}, nil
+}
+
+// Lock zeroizes the seed, master key and derived private keys, leaving only
+// the copy of the seed and mnemonic that was sealed under
+// WalletConfig.LockPassphrase when the wallet was created. Derived accounts
+// and their addresses remain listed, but operations needing a private key
+// return ErrWalletLocked until the wallet is unlocked. It returns
+// ErrNoLockPassphrase if the wallet has no lock passphrase, as it could not
+// be unlocked again. Locking a locked wallet does nothing.
+//
+// The mnemonic is a Go string and cannot be overwritten in place; Lock drops
+// the wallet's reference to it. Lock also clears Account.PrivateKey on the
+// accounts already returned to callers, so code must not read that field
+// directly while the wallet may lock.
+func (w *SimpleWallet) Lock() error {
+	w.mu.Lock()
+	locked, err := w.lock()
+	w.mu.Unlock()
+
+	if locked {
+		w.notify(LockEvent{Locked: true, Reason: LockManual, Time: time.Now()})
+	}
+	return err
+}
+
+// lock locks the wallet with w.mu held and reports whether its state changed
+func (w *SimpleWallet) lock() (bool, error) {
+	if w.isLocked {
+		return false, nil
+	}
+	if w.sealed == nil && !w.isClosed {
+		return false, ErrNoLockPassphrase
+	}
+
+	w.zeroize()
+	w.isLocked = true
+	if w.lockTimer != nil {
+		w.lockTimer.Stop()
+		w.lockTimer = nil
+	}
+	return true, nil
+}
+
+// notify passes event to the OnLockEvent hook. It is called without w.mu
+// held so the hook may use the wallet.
+func (w *SimpleWallet) notify(event LockEvent) {
+	if w.onLockEvent != nil {
+		w.onLockEvent(event)
+	}
+}
+
+// zeroize clears the plaintext key material while keeping the accounts and
+// their paths
+func (w *SimpleWallet) zeroize() {
+	if w.seed != nil {
+		secureClear(w.seed)
+		w.seed = nil
+	}
+	if w.masterKey != nil {
+		w.masterKey.Zero()
+		w.masterKey = nil
+	}
+	w.mnemonic = ""
+
+	for _, account := range w.accounts {
+		if account.PrivateKey != nil {
+			secureClearPrivateKey(account.PrivateKey)
+			account.PrivateKey = nil
+		}
+	}
+}
+
+// Unlock decrypts the seed and mnemonic with the lock passphrase and
+// re-derives the private keys of every derived account. It returns
+// ErrInvalidPassphrase if the passphrase is wrong. Unlocking an unlocked
+// wallet does nothing.
+func (w *SimpleWallet) Unlock(passphrase string) error {
+	// Derive the key before taking w.mu: the sealed copy never changes once
+	// the wallet is created, and the KDF is deliberately slow
+	w.mu.RLock()
+	isLocked, sealed := w.isLocked, w.sealed
+	w.mu.RUnlock()
+	if !isLocked {
+		return nil
+	}
+	if sealed == nil {
+		return fmt.Errorf("%w: wallet is closed", ErrWalletLocked)
+	}
+
+	password := []byte(passphrase)
+	defer secureClear(password)
+	aead, err := lockAEAD(password, sealed.salt)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	unlocked, err := w.unlock(sealed, aead)
+	w.mu.Unlock()
+
+	if unlocked {
+		w.notify(LockEvent{Locked: false, Time: time.Now()})
+	}
+	return err
+}
+
+// unlock opens the sealed secrets with aead with w.mu held and reports
+// whether the wallet's state changed
+func (w *SimpleWallet) unlock(sealed *sealedSecrets, aead cipher.AEAD) (bool, error) {
+	if !w.isLocked {
+		return false, nil
+	}
+	if w.sealed != sealed {
+		return false, fmt.Errorf("%w: wallet is closed", ErrWalletLocked)
+	}
+
+	plaintext, err := aead.Open(nil, sealed.nonce, sealed.ciphertext, nil)
+	if err != nil {
+		return false, ErrInvalidPassphrase
+	}
+	defer secureClear(plaintext)
+
+	masterKey, err := NewMasterKey(plaintext[:SeedLength])
+	if err != nil {
+		return false, fmt.Errorf("failed to create master key: %w", err)
+	}
+	w.seed = append([]byte(nil), plaintext[:SeedLength]...)
+	w.mnemonic = string(plaintext[SeedLength:])
+	w.masterKey = masterKey
+
+	for address, path := range w.paths {
+		privateKey, err := w.derivePrivateKey(path)
+		if err != nil {
+			w.zeroize()
+			return false, fmt.Errorf("key derivation failed: %w", err)
+		}
+		w.accounts[address].PrivateKey = privateKey
+		w.accounts[address].PublicKey = &privateKey.PublicKey
+	}
+
+	w.isLocked = false
+	w.startAutoLock()
+	return true, nil
+}
+
+// IsLocked reports whether the wallet is locked
+func (w *SimpleWallet) IsLocked() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.isLocked
+}
+
+// touch records a use of the wallet's keys for the idle auto-lock
+func (w *SimpleWallet) touch() {
+	w.lastUsed.Store(time.Now().UnixNano())
+}
+
+// LastUsed returns the time the wallet last derived an account, exported a
+// private key or signed
+func (w *SimpleWallet) LastUsed() time.Time {
+	return time.Unix(0, w.lastUsed.Load())
+}
+
+// startAutoLock records the start of an unlocked period and arms the
+// auto-lock timer when AutoLockIdle or AutoLockMax is set. It is called with
+// w.mu held.
+func (w *SimpleWallet) startAutoLock() {
+	w.unlockedAt = time.Now()
+	w.touch()
+	if w.autoLockIdle > 0 || w.autoLockMax > 0 {
+		w.lockTimer = time.AfterFunc(w.nextAutoLock(w.unlockedAt), w.autoLock)
+	}
+}
+
+// nextAutoLock returns the time from now until the earliest auto-lock
+// deadline
+func (w *SimpleWallet) nextAutoLock(now time.Time) time.Duration {
+	var next time.Duration = -1
+	if w.autoLockIdle > 0 {
+		next = w.LastUsed().Add(w.autoLockIdle).Sub(now)
+	}
+	if w.autoLockMax > 0 {
+		if untilMax := w.unlockedAt.Add(w.autoLockMax).Sub(now); next < 0 || untilMax < next {
+			next = untilMax
+		}
+	}
+	return next
+}
+
+// autoLock runs when the auto-lock timer fires. It locks the wallet if a
+// deadline has passed, or re-arms the timer for the next one when the wallet
+// was used in the meantime.
+func (w *SimpleWallet) autoLock() {
+	w.mu.Lock()
+	if w.isLocked || w.lockTimer == nil {
+		w.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	var reason LockReason
+	switch {
+	case w.autoLockMax > 0 && now.Sub(w.unlockedAt) >= w.autoLockMax:
+		reason = LockExpired
+	case w.autoLockIdle > 0 && now.Sub(w.LastUsed()) >= w.autoLockIdle:
+		reason = LockIdle
+	default:
+		w.lockTimer.Reset(w.nextAutoLock(now))
+		w.mu.Unlock()
+		return
+	}
+
+	locked, _ := w.lock()
+	w.mu.Unlock()
+
+	if locked {
+		w.notify(LockEvent{Locked: true, Reason: reason, Time: now})
+	}
+}
@@ -0,0 +1,325 @@
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLockUnlock(t *testing.T) {
+	config := DefaultConfig()
+	config.Passphrase = "TREZOR"
+	config.LockPassphrase = "lock secret"
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	other, err := wallet.DeriveAtPathString("m/44'/60'/1'/0/0")
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	keyHex, _ := wallet.GetPrivateKeyHex(account.Address)
+	otherKeyHex, _ := wallet.GetPrivateKeyHex(other.Address)
+	hash := keccak256([]byte("lock"))
+	sig, err := wallet.SignHash(account.Address, hash)
+	if err != nil {
+		t.Fatalf("SignHash failed: %v", err)
+	}
+	oldKey := account.PrivateKey
+
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if !wallet.IsLocked() || wallet.Status() != "Locked" {
+		t.Fatal("Wallet does not report locked")
+	}
+
+	// Key material is gone from memory
+	if wallet.seed != nil || wallet.masterKey != nil || wallet.mnemonic != "" {
+		t.Error("Lock left plaintext secrets in the wallet")
+	}
+	if account.PrivateKey != nil || oldKey.D.Sign() != 0 {
+		t.Error("Lock did not zeroize the account private key")
+	}
+	if bytes.Contains(wallet.sealed.ciphertext, []byte("volcano")) {
+		t.Error("Sealed copy contains the plaintext mnemonic")
+	}
+
+	// Public information stays available
+	if len(wallet.Accounts()) != 2 {
+		t.Errorf("Locked wallet lists %d accounts, want 2", len(wallet.Accounts()))
+	}
+	if _, err := wallet.GetPublicKeyHex(account.Address); err != nil {
+		t.Errorf("GetPublicKeyHex failed while locked: %v", err)
+	}
+
+	// Everything needing a private key fails
+	if _, err := wallet.GetPrivateKeyHex(account.Address); err != ErrWalletLocked {
+		t.Errorf("GetPrivateKeyHex error = %v, want ErrWalletLocked", err)
+	}
+	if _, err := wallet.GetMnemonic(); err != ErrWalletLocked {
+		t.Errorf("GetMnemonic error = %v, want ErrWalletLocked", err)
+	}
+	if _, err := wallet.Derive(1); err != ErrWalletLocked {
+		t.Errorf("Derive error = %v, want ErrWalletLocked", err)
+	}
+	if _, err := wallet.SignHash(account.Address, hash); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("SignHash error = %v, want ErrWalletLocked", err)
+	}
+
+	// Wrong passphrases, including the BIP-39 one, are rejected and the
+	// wallet stays locked
+	for _, wrong := range []string{"", "TREZOR"} {
+		if err := wallet.Unlock(wrong); err != ErrInvalidPassphrase {
+			t.Errorf("Unlock(%q) error = %v, want ErrInvalidPassphrase", wrong, err)
+		}
+	}
+	if !wallet.IsLocked() {
+		t.Error("Wrong passphrase unlocked the wallet")
+	}
+
+	if err := wallet.Unlock("lock secret"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if wallet.IsLocked() {
+		t.Fatal("Wallet still locked after Unlock")
+	}
+	if got, _ := wallet.GetPrivateKeyHex(account.Address); got != keyHex {
+		t.Error("Unlock restored a different private key")
+	}
+	if got, _ := wallet.GetPrivateKeyHex(other.Address); got != otherKeyHex {
+		t.Error("Unlock restored a different private key for the path account")
+	}
+	if mnemonic, _ := wallet.GetMnemonic(); mnemonic != demoMnemonic {
+		t.Errorf("GetMnemonic = %q after Unlock", mnemonic)
+	}
+	if again, _ := wallet.SignHash(account.Address, hash); !bytes.Equal(again, sig) {
+		t.Error("Signature changed across Lock and Unlock")
+	}
+	if _, err := wallet.Derive(1); err != nil {
+		t.Errorf("Derive failed after Unlock: %v", err)
+	}
+
+	// Later locks reuse the sealed copy
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Second Lock failed: %v", err)
+	}
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock of a locked wallet failed: %v", err)
+	}
+	if err := wallet.Unlock("lock secret"); err != nil {
+		t.Fatalf("Second Unlock failed: %v", err)
+	}
+	if err := wallet.Unlock("wrong"); err != nil {
+		t.Errorf("Unlock of an unlocked wallet failed: %v", err)
+	}
+}
+
+func TestLockSeedWallet(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, SeedLength)
+	wallet, err := NewFromSeed(seed, &WalletConfig{LockPassphrase: "lock secret"})
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if err := wallet.Unlock("lock secret"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if _, err := wallet.GetPrivateKeyHex(account.Address); err != nil {
+		t.Errorf("GetPrivateKeyHex failed after Unlock: %v", err)
+	}
+
+	// A closed wallet cannot be unlocked again
+	wallet.Close()
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock of a closed wallet failed: %v", err)
+	}
+	if err := wallet.Unlock("lock secret"); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("Unlock of a closed wallet error = %v, want ErrWalletLocked", err)
+	}
+}
+
+func TestLockWithoutPassphrase(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Nothing was sealed, so locking would lose the keys for good
+	if err := wallet.Lock(); !errors.Is(err, ErrNoLockPassphrase) {
+		t.Errorf("Lock error = %v, want ErrNoLockPassphrase", err)
+	}
+	if wallet.IsLocked() || wallet.sealed != nil {
+		t.Error("Wallet without a lock passphrase was locked")
+	}
+	if _, err := wallet.GetMnemonic(); err != nil {
+		t.Errorf("GetMnemonic failed after refused Lock: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.AutoLockIdle = time.Minute
+	if _, err := NewFromMnemonic(demoMnemonic, config); !errors.Is(err, ErrNoLockPassphrase) {
+		t.Errorf("Auto-lock without a lock passphrase error = %v, want ErrNoLockPassphrase", err)
+	}
+}
+
+// lockEvents sets the lock passphrase "lock secret" and returns a channel
+// that the config's OnLockEvent hook sends to
+func lockEvents(config *WalletConfig) chan LockEvent {
+	config.LockPassphrase = "lock secret"
+	events := make(chan LockEvent, 8)
+	config.OnLockEvent = func(event LockEvent) { events <- event }
+	return events
+}
+
+// waitLockEvent waits for the next lock event
+func waitLockEvent(t *testing.T, events chan LockEvent) LockEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for a lock event")
+		return LockEvent{}
+	}
+}
+
+func TestLockEvents(t *testing.T) {
+	config := DefaultConfig()
+	events := lockEvents(config)
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	if err := wallet.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if event := waitLockEvent(t, events); !event.Locked || event.Reason != LockManual {
+		t.Errorf("Lock event = %+v, want a manual lock", event)
+	}
+	if err := wallet.Unlock("lock secret"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if event := waitLockEvent(t, events); event.Locked || event.Reason != "" {
+		t.Errorf("Unlock event = %+v, want an unlock", event)
+	}
+
+	// Locking a locked wallet, unlocking an unlocked one and failing to
+	// unlock are not events
+	wallet.Unlock("lock secret")
+	wallet.Lock()
+	waitLockEvent(t, events)
+	wallet.Lock()
+	wallet.Unlock("wrong")
+	if len(events) != 0 {
+		t.Errorf("Got %d unexpected events", len(events))
+	}
+}
+
+func TestAutoLockIdle(t *testing.T) {
+	config := DefaultConfig()
+	config.AutoLockIdle = 200 * time.Millisecond
+	events := lockEvents(config)
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Regular use keeps the wallet unlocked past the idle timeout
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	for i := 0; i < 8; i++ {
+		time.Sleep(50 * time.Millisecond)
+		if _, err := wallet.SignHash(account.Address, keccak256([]byte("idle"))); err != nil {
+			t.Fatalf("SignHash failed after %d uses: %v", i, err)
+		}
+	}
+	if time.Since(wallet.LastUsed()) > 50*time.Millisecond {
+		t.Errorf("LastUsed = %v, want the last signature", wallet.LastUsed())
+	}
+
+	used := wallet.LastUsed()
+	event := waitLockEvent(t, events)
+	if !event.Locked || event.Reason != LockIdle {
+		t.Errorf("Lock event = %+v, want an idle lock", event)
+	}
+	if idle := event.Time.Sub(used); idle < config.AutoLockIdle {
+		t.Errorf("Locked after %v idle, want at least %v", idle, config.AutoLockIdle)
+	}
+	if !wallet.IsLocked() || account.PrivateKey != nil {
+		t.Error("Idle wallet was not locked")
+	}
+
+	// Unlocking re-arms the timer
+	if err := wallet.Unlock("lock secret"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	waitLockEvent(t, events)
+	if event := waitLockEvent(t, events); event.Reason != LockIdle {
+		t.Errorf("Lock event after unlock = %+v, want an idle lock", event)
+	}
+}
+
+func TestAutoLockMax(t *testing.T) {
+	config := DefaultConfig()
+	config.AutoLockIdle = time.Hour
+	config.AutoLockMax = 300 * time.Millisecond
+	events := lockEvents(config)
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Use does not extend the unlocked period
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for !wallet.IsLocked() {
+			wallet.Derive(0)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	event := waitLockEvent(t, events)
+	<-done
+	if !event.Locked || event.Reason != LockExpired {
+		t.Errorf("Lock event = %+v, want an expired lock", event)
+	}
+}
+
+func TestAutoLockClose(t *testing.T) {
+	config := DefaultConfig()
+	config.AutoLockIdle = 50 * time.Millisecond
+	events := lockEvents(config)
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	wallet.Close()
+
+	time.Sleep(150 * time.Millisecond)
+	if len(events) != 0 {
+		t.Errorf("Closed wallet sent %d lock events", len(events))
+	}
+}
@@ -0,0 +1,345 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manager errors
+var (
+	ErrWalletNotFound  = errors.New("wallet not found")
+	ErrAmbiguousWallet = errors.New("wallet label is ambiguous")
+	ErrWalletOpen      = errors.New("wallet is already open")
+	ErrDuplicateWallet = errors.New("duplicate wallet id")
+)
+
+// WalletInfo describes a vault known to a Manager
+type WalletInfo struct {
+	VaultInfo
+	// Path is the vault file
+	Path string
+	// Open reports whether the manager holds an instance of the wallet, and
+	// Locked whether that instance is locked
+	Open   bool
+	Locked bool
+}
+
+// managedWallet is a Manager's index entry for one vault
+type managedWallet struct {
+	info   VaultInfo
+	path   string
+	wallet *SimpleWallet
+}
+
+// Manager indexes a directory of wallet vault files by ID and label and
+// holds at most one open instance of each wallet
+type Manager struct {
+	dir     string
+	config  *WalletConfig
+	wallets map[string]*managedWallet
+	// problems holds the vault files skipped by the last Refresh
+	problems []error
+	mu       sync.Mutex
+}
+
+// NewManager indexes the vault files (*.json) in dir. Wallets opened through
+// the manager use config for their options, such as auto-lock.
+func NewManager(dir string, config *WalletConfig) (*Manager, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	m := &Manager{
+		dir:     dir,
+		config:  config,
+		wallets: make(map[string]*managedWallet),
+	}
+	if err := m.Refresh(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Refresh re-reads the vault headers in the manager's directory. Files that
+// are not wallet vaults are skipped, and open wallets stay open. Vaults that
+// cannot be read, and later files (in name order) holding the ID of an earlier
+// one, are skipped and reported by Problems. Only a directory that cannot be
+// read is an error.
+func (m *Manager) Refresh() error {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wallets := make(map[string]*managedWallet)
+	var problems []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(m.dir, name)
+		info, err := ReadVaultInfo(path)
+		if errors.Is(err, ErrInvalidVault) {
+			continue
+		}
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if existing, exists := wallets[info.ID]; exists {
+			problems = append(problems, fmt.Errorf("%w: %s in %s and %s", ErrDuplicateWallet, info.ID, existing.path, path))
+			continue
+		}
+		wallets[info.ID] = &managedWallet{info: *info, path: path}
+	}
+
+	// Carry open instances over, even if their file has gone
+	for id, old := range m.wallets {
+		if old.wallet == nil || old.wallet.closed() {
+			continue
+		}
+		if current, exists := wallets[id]; exists {
+			current.wallet = old.wallet
+		} else {
+			wallets[id] = old
+		}
+	}
+	m.wallets = wallets
+	m.problems = problems
+	return nil
+}
+
+// Problems returns the errors for the vault files skipped by the last
+// Refresh, such as unreadable files and duplicate wallet IDs
+func (m *Manager) Problems() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]error(nil), m.problems...)
+}
+
+// List returns the indexed wallets ordered by label and then ID
+func (m *Manager) List() []WalletInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]WalletInfo, 0, len(m.wallets))
+	for _, entry := range m.wallets {
+		list = append(list, entry.walletInfo())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Label != list[j].Label {
+			return list[i].Label < list[j].Label
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Lookup returns the wallet with the given ID or, failing that, the only
+// wallet with the given label
+func (m *Manager) Lookup(ref string) (WalletInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(ref)
+	if err != nil {
+		return WalletInfo{}, err
+	}
+	return entry.walletInfo(), nil
+}
+
+// FindAddress returns the wallets holding an account with the given address,
+// ordered as List. Open wallets are searched by their current accounts, so
+// accounts derived since the vault was saved are found too.
+func (m *Manager) FindAddress(address Address) []WalletInfo {
+	var found []WalletInfo
+	for _, info := range m.List() {
+		for _, candidate := range info.Addresses {
+			if candidate == address {
+				found = append(found, info)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// Open opens the wallet with the given ID or label using its vault
+// passphrase. A wallet has at most one instance: if the manager already holds
+// it locked, Open unlocks and returns that instance, and if it is unlocked
+// Open returns ErrWalletOpen. The vault is decrypted without blocking the
+// manager, so of two concurrent opens the second to finish gets
+// ErrWalletOpen.
+func (m *Manager) Open(ref, passphrase string) (*SimpleWallet, error) {
+	m.mu.Lock()
+	entry, err := m.lookup(ref)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if w := entry.openWallet(); w != nil {
+		m.mu.Unlock()
+		if !w.IsLocked() {
+			return nil, fmt.Errorf("%w: %s", ErrWalletOpen, entry.info.ID)
+		}
+		// Unlock outside m.mu: it calls the OnLockEvent hook, which may
+		// use the manager
+		if err := w.Unlock(passphrase); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	id, path := entry.info.ID, entry.path
+	m.mu.Unlock()
+
+	// Decrypt without m.mu: the vault KDF is deliberately slow
+	w, err := OpenWallet(path, passphrase, m.config)
+	if err != nil {
+		return nil, err
+	}
+	if w.ID() != id {
+		w.Close()
+		return nil, fmt.Errorf("%w: %s now holds wallet %s", ErrInvalidVault, path, w.ID())
+	}
+
+	// Another Open may have won the race, or a Refresh dropped the vault
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, exists := m.wallets[id]
+	if !exists {
+		w.Close()
+		return nil, fmt.Errorf("%w: %s", ErrWalletNotFound, id)
+	}
+	if entry.openWallet() != nil {
+		w.Close()
+		return nil, fmt.Errorf("%w: %s", ErrWalletOpen, id)
+	}
+	entry.wallet = w
+	return w, nil
+}
+
+// Wallet returns the open instance of the wallet with the given ID or label,
+// or ErrWalletNotFound if the manager does not hold one
+func (m *Manager) Wallet(ref string) (*SimpleWallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	w := entry.openWallet()
+	if w == nil {
+		return nil, fmt.Errorf("%w: %s is not open", ErrWalletNotFound, entry.info.ID)
+	}
+	return w, nil
+}
+
+// Close closes the open instance of the wallet with the given ID or label.
+// Closing a wallet that is not open does nothing.
+func (m *Manager) Close(ref string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(ref)
+	if err != nil {
+		return err
+	}
+	if entry.wallet != nil {
+		entry.wallet.Close()
+		entry.wallet = nil
+	}
+	return nil
+}
+
+// CloseAll closes every open wallet
+func (m *Manager) CloseAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, entry := range m.wallets {
+		if entry.wallet != nil {
+			entry.wallet.Close()
+			entry.wallet = nil
+		}
+	}
+}
+
+// lookup finds a wallet by ID, then by label, with m.mu held
+func (m *Manager) lookup(ref string) (*managedWallet, error) {
+	if entry, exists := m.wallets[ref]; exists {
+		return entry, nil
+	}
+
+	var match *managedWallet
+	for _, entry := range m.wallets {
+		if label := entry.label(); label != "" && label == ref {
+			if match != nil {
+				return nil, fmt.Errorf("%w: %q", ErrAmbiguousWallet, ref)
+			}
+			match = entry
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %q", ErrWalletNotFound, ref)
+	}
+	return match, nil
+}
+
+// openWallet returns the entry's open instance, forgetting one that was
+// closed directly
+func (e *managedWallet) openWallet() *SimpleWallet {
+	if e.wallet != nil && e.wallet.closed() {
+		e.wallet = nil
+	}
+	return e.wallet
+}
+
+// label returns the wallet label, preferring that of the open instance
+func (e *managedWallet) label() string {
+	if w := e.openWallet(); w != nil {
+		return w.Label()
+	}
+	return e.info.Label
+}
+
+// walletInfo describes the entry. For an open instance it uses the current
+// label and adds accounts derived since the vault was read.
+func (e *managedWallet) walletInfo() WalletInfo {
+	info := WalletInfo{VaultInfo: e.info, Path: e.path}
+	info.Addresses = append([]Address(nil), e.info.Addresses...)
+
+	if w := e.openWallet(); w != nil {
+		info.Open = true
+		info.Locked = w.IsLocked()
+		info.Label = w.Label()
+
+		known := make(map[Address]bool, len(info.Addresses))
+		for _, address := range info.Addresses {
+			known[address] = true
+		}
+		var added []Address
+		for _, account := range w.Accounts() {
+			if !known[account.Address] {
+				added = append(added, account.Address)
+			}
+		}
+		sort.Slice(added, func(i, j int) bool {
+			return added[i].Hex() < added[j].Hex()
+		})
+		info.Addresses = append(info.Addresses, added...)
+	}
+	return info
+}
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// saveTestVault saves a wallet with one account to dir/name and returns it
+// closed, along with its account address
+func saveTestVault(t *testing.T, dir, name, label, mnemonic string) (string, Address) {
+	t.Helper()
+
+	w, err := NewFromMnemonic(mnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer w.Close()
+	w.SetLabel(label)
+	account, err := w.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	if err := w.Save(filepath.Join(dir, name), "secret"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	return w.ID(), account.Address
+}
+
+func TestManager(t *testing.T) {
+	useFastVaultKDF(t)
+	dir := t.TempDir()
+
+	otherMnemonic := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	treasuryID, treasuryAddr := saveTestVault(t, dir, "treasury.json", "treasury", demoMnemonic)
+	payrollID, payrollAddr := saveTestVault(t, dir, "payroll.json", "payroll", otherMnemonic)
+	// Other files in the directory are ignored
+	os.WriteFile(filepath.Join(dir, "notes.json"), []byte(`{"todo": []}`), 0o600)
+	os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("wallets"), 0o600)
+
+	m, err := NewManager(dir, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer m.CloseAll()
+
+	list := m.List()
+	if len(list) != 2 || list[0].ID != payrollID || list[1].ID != treasuryID {
+		t.Fatalf("List = %+v, want payroll then treasury", list)
+	}
+	if list[1].Path != filepath.Join(dir, "treasury.json") || list[1].Open || len(list[1].Addresses) != 1 {
+		t.Errorf("Treasury entry = %+v", list[1])
+	}
+
+	for _, ref := range []string{treasuryID, "treasury"} {
+		if info, err := m.Lookup(ref); err != nil || info.ID != treasuryID {
+			t.Errorf("Lookup(%q) = %s, %v", ref, info.ID, err)
+		}
+	}
+	if _, err := m.Lookup("savings"); !errors.Is(err, ErrWalletNotFound) {
+		t.Errorf("Lookup error = %v, want ErrWalletNotFound", err)
+	}
+
+	// Addresses are found without opening any wallet
+	if found := m.FindAddress(payrollAddr); len(found) != 1 || found[0].ID != payrollID {
+		t.Errorf("FindAddress = %+v, want payroll", found)
+	}
+
+	// One instance per wallet
+	if _, err := m.Wallet("treasury"); !errors.Is(err, ErrWalletNotFound) {
+		t.Errorf("Wallet error = %v, want ErrWalletNotFound", err)
+	}
+	if _, err := m.Open("treasury", "wrong"); err != ErrInvalidPassphrase {
+		t.Errorf("Open error = %v, want ErrInvalidPassphrase", err)
+	}
+	w, err := m.Open("treasury", "secret")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := m.Open(treasuryID, "secret"); !errors.Is(err, ErrWalletOpen) {
+		t.Errorf("Second Open error = %v, want ErrWalletOpen", err)
+	}
+	if got, err := m.Wallet(treasuryID); err != nil || got != w {
+		t.Errorf("Wallet = %p, %v, want the open instance", got, err)
+	}
+
+	// A locked instance is unlocked in place
+	if err := w.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if info, _ := m.Lookup("treasury"); !info.Open || !info.Locked {
+		t.Errorf("Locked wallet info = %+v", info)
+	}
+	if again, err := m.Open("treasury", "secret"); err != nil || again != w || w.IsLocked() {
+		t.Errorf("Open of a locked wallet = %p, %v, want the unlocked instance", again, err)
+	}
+
+	// Accounts derived in an open wallet are found before it is saved
+	derived, err := w.Derive(5)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	if found := m.FindAddress(derived.Address); len(found) != 1 || found[0].ID != treasuryID {
+		t.Errorf("FindAddress = %+v, want treasury", found)
+	}
+	if found := m.FindAddress(treasuryAddr); len(found) != 1 {
+		t.Errorf("FindAddress = %+v, want treasury", found)
+	}
+
+	// Labels follow the open instance
+	w.SetLabel("reserve")
+	if _, err := m.Lookup("reserve"); err != nil {
+		t.Errorf("Lookup by new label failed: %v", err)
+	}
+
+	if err := m.Close("reserve"); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !w.closed() {
+		t.Error("Close did not close the wallet")
+	}
+	if info, _ := m.Lookup(treasuryID); info.Open || info.Label != "treasury" {
+		t.Errorf("Closed wallet info = %+v", info)
+	}
+
+	// A wallet closed directly can be opened again
+	w, err = m.Open(payrollID, "secret")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	w.Close()
+	if _, err := m.Open(payrollID, "secret"); err != nil {
+		t.Errorf("Reopen after Close failed: %v", err)
+	}
+}
+
+func TestManagerLabelsAndDuplicates(t *testing.T) {
+	useFastVaultKDF(t)
+	dir := t.TempDir()
+
+	saveTestVault(t, dir, "a.json", "customer", demoMnemonic)
+	saveTestVault(t, dir, "b.json", "customer", demoMnemonic)
+
+	m, err := NewManager(dir, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	if _, err := m.Open("customer", "secret"); !errors.Is(err, ErrAmbiguousWallet) {
+		t.Errorf("Open error = %v, want ErrAmbiguousWallet", err)
+	}
+	if found := m.FindAddress(m.List()[0].Addresses[0]); len(found) != 2 {
+		t.Errorf("FindAddress found %d wallets, want 2", len(found))
+	}
+
+	// A copied vault has the same ID as its original, which is kept. A file
+	// that cannot be read is skipped without hiding the other wallets.
+	data, _ := os.ReadFile(filepath.Join(dir, "a.json"))
+	os.WriteFile(filepath.Join(dir, "z-copy.json"), data, 0o600)
+	if err := os.Symlink(filepath.Join(dir, "gone"), filepath.Join(dir, "broken.json")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+	if err := m.Refresh(); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if list := m.List(); len(list) != 2 || list[0].Path == filepath.Join(dir, "z-copy.json") || list[1].Path == filepath.Join(dir, "z-copy.json") {
+		t.Errorf("List = %+v, want a.json and b.json", list)
+	}
+	problems := m.Problems()
+	if len(problems) != 2 || !errors.Is(problems[0], os.ErrNotExist) || !errors.Is(problems[1], ErrDuplicateWallet) {
+		t.Errorf("Problems = %v, want a missing file and a duplicate wallet", problems)
+	}
+	if _, err := NewManager(dir, nil); err != nil {
+		t.Errorf("NewManager failed: %v", err)
+	}
+
+	if _, err := NewManager(filepath.Join(dir, "missing"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("NewManager error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestManagerConcurrentOpen(t *testing.T) {
+	useFastVaultKDF(t)
+	dir := t.TempDir()
+	id, _ := saveTestVault(t, dir, "treasury.json", "treasury", demoMnemonic)
+
+	m, err := NewManager(dir, nil)
+	if err != nil {
+		t.Fatalf("NewManager failed: %v", err)
+	}
+	defer m.CloseAll()
+
+	// Racing opens decrypt in parallel, and exactly one instance is kept
+	const opens = 4
+	results := make(chan *SimpleWallet, opens)
+	errs := make(chan error, opens)
+	for i := 0; i < opens; i++ {
+		go func() {
+			w, err := m.Open(id, "secret")
+			if err != nil {
+				errs <- err
+				return
+			}
+			results <- w
+		}()
+	}
+
+	var opened *SimpleWallet
+	for i := 0; i < opens; i++ {
+		select {
+		case w := <-results:
+			if opened != nil {
+				t.Fatal("Two opens returned an instance")
+			}
+			opened = w
+		case err := <-errs:
+			if !errors.Is(err, ErrWalletOpen) {
+				t.Errorf("Open error = %v, want ErrWalletOpen", err)
+			}
+		}
+	}
+	if got, err := m.Wallet(id); err != nil || got != opened || opened.closed() {
+		t.Errorf("Wallet = %p, %v, want the instance returned by Open %p", got, err, opened)
+	}
+}
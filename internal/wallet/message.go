@@ -0,0 +1,39 @@
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// messagePrefix starts every EIP-191 version 0x45 (personal_sign) message
+const messagePrefix = "\x19Ethereum Signed Message:\n"
+
+// TextHash returns the EIP-191 personal_sign hash of a message:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func TextHash(message []byte) []byte {
+	return keccak256([]byte(messagePrefix+strconv.Itoa(len(message))), message)
+}
+
+// SignMessage signs a message with the EIP-191 personal_sign prefix and
+// returns a 65-byte [R || S || V] signature with V in {27, 28}, the form
+// produced by personal_sign and eth_sign
+func (w *SimpleWallet) SignMessage(address Address, message []byte) ([]byte, error) {
+	sig, err := w.SignHash(address, TextHash(message))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	sig[RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// RecoverMessageAddress returns the address that signed a personal_sign
+// message. V may be 0/1 or 27/28.
+func RecoverMessageAddress(message, sig []byte) (Address, error) {
+	return RecoverAddress(TextHash(message), sig)
+}
+
+// VerifyMessage reports whether sig is a personal_sign signature of message
+// by address
+func VerifyMessage(address Address, message, sig []byte) bool {
+	return VerifySignature(address, TextHash(message), sig)
+}
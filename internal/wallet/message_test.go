@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestTextHash(t *testing.T) {
+	tests := []struct {
+		message string
+		hash    string
+	}{
+		{"Some data", "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655"},
+	}
+
+	for _, tt := range tests {
+		if got := hex.EncodeToString(TextHash([]byte(tt.message))); got != tt.hash {
+			t.Errorf("TextHash(%q) = %s, want %s", tt.message, got, tt.hash)
+		}
+	}
+}
+
+func TestSignMessageVector(t *testing.T) {
+	// web3.js accounts.sign documentation example
+	d, _ := new(big.Int).SetString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", 16)
+	message := []byte("Some data")
+	want := "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd" +
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a029" + "1c"
+
+	sig, err := signHash(d, TextHash(message))
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	sig[RecoveryIDOffset] += 27
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("Signature = %s, want %s", got, want)
+	}
+
+	address := HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	if got, err := RecoverMessageAddress(message, sig); err != nil || got != address {
+		t.Errorf("RecoverMessageAddress = %s, %v; want %s", got.Hex(), err, address.Hex())
+	}
+	if !VerifyMessage(address, message, sig) {
+		t.Error("VerifyMessage rejected the signature")
+	}
+	if VerifyMessage(address, []byte("Some data\n"), sig) {
+		t.Error("VerifyMessage accepted a different message")
+	}
+}
+
+func TestWalletSignMessage(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	other, err := wallet.Derive(1)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	messages := [][]byte{{}, []byte("I own this address"), make([]byte, 1000)}
+	for _, message := range messages {
+		sig, err := wallet.SignMessage(account.Address, message)
+		if err != nil {
+			t.Fatalf("SignMessage failed: %v", err)
+		}
+		if v := sig[RecoveryIDOffset]; v != 27 && v != 28 {
+			t.Errorf("V = %d, want 27 or 28", v)
+		}
+		if !VerifyMessage(account.Address, message, sig) {
+			t.Errorf("Signature of %d-byte message does not verify", len(message))
+		}
+		if VerifyMessage(other.Address, message, sig) {
+			t.Errorf("Signature of %d-byte message verified for another address", len(message))
+		}
+	}
+
+	wallet.isLocked = true
+	if _, err := wallet.SignMessage(account.Address, []byte("locked")); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("Expected ErrWalletLocked, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import "strings"
+
+//go:generate go run gen_nfkd.go
+
+// Hangul syllable decomposition constants from the Unicode standard, section 3.12
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = 11172
+)
+
+// normalizeNFKD returns the Unicode Normalization Form KD of s, as required by
+// BIP-39 for both the mnemonic and the passphrase before seed derivation
+func normalizeNFKD(s string) string {
+	// Fast path: ASCII input is already normalized
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	// Full compatibility decomposition
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		if r >= hangulSBase && r < hangulSBase+hangulSCount {
+			index := r - hangulSBase
+			runes = append(runes, hangulLBase+index/hangulNCount)
+			runes = append(runes, hangulVBase+(index%hangulNCount)/hangulTCount)
+			if t := index % hangulTCount; t != 0 {
+				runes = append(runes, hangulTBase+t)
+			}
+			continue
+		}
+		if decomposition, exists := nfkdDecompositions[r]; exists {
+			runes = append(runes, []rune(decomposition)...)
+			continue
+		}
+		runes = append(runes, r)
+	}
+
+	// Canonical ordering: stable sort each run of combining marks by class
+	for i := 1; i < len(runes); i++ {
+		class := combiningClasses[runes[i]]
+		if class == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := combiningClasses[runes[j-1]]
+			if prev == 0 || prev <= class {
+				break
+			}
+			runes[j-1], runes[j] = runes[j], runes[j-1]
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range runes {
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package wallet
+
+import "testing"
+
+func TestNormalizeNFKD(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ASCII", "TREZOR", "TREZOR"},
+		{"Empty", "", ""},
+		{"Composed Latin", "Crème brûlée", "Cre\u0300me bru\u0302le\u0301e"},
+		{"Compatibility ligature", "ﬁ", "fi"},
+		{"Circled digit", "①", "1"},
+		{"Full-width letters", "ＡＢＣ", "ABC"},
+		{"Non-breaking space", "a\u00a0b", "a b"},
+		{"Hangul LV syllable", "가", "\u1100\u1161"},
+		{"Hangul LVT syllable", "한", "\u1112\u1161\u11ab"},
+		{"Canonical reordering", "a\u0302\u0323", "a\u0323\u0302"},
+		{"Decomposition then reordering", "\u1e0b\u0323", "d\u0323\u0307"},
+		{"Japanese ideographic space", "あいこくしん\u3000あいこくしん", "あいこくしん あいこくしん"},
+		{"Half-width katakana", "ｶﾞ", "\u30ab\u3099"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeNFKD(tt.input); got != tt.expected {
+				t.Errorf("normalizeNFKD(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package wallet
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func TestPBKDF2Key(t *testing.T) {
+	tests := []struct {
+		name       string
+		hash       func() hash.Hash
+		password   string
+		salt       string
+		iterations int
+		keyLen     int
+		expected   string
+	}{
+		// RFC 6070
+		{"SHA-1 4096 iterations", sha1.New, "password", "salt", 4096, 20, "4b007901b765489abead49d926f721d065a429c1"},
+		// RFC 7914, section 11
+		{"SHA-256 1 iteration", sha256.New, "passwd", "salt", 1, 64, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"SHA-256 80000 iterations", sha256.New, "Password", "NaCl", 80000, 64, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+		{"SHA-512 1 iteration", sha512.New, "password", "salt", 1, 64, "867f70cf1ade02cff3752599a3a53dc4af34c7a669815ae5d513554e1c8cf252c02d470a285a0501bad999bfe943c08f050235d7d68b1da55e63f73b60a57fce"},
+		{"SHA-512 2 iterations", sha512.New, "password", "salt", 2, 64, "e1d9c16aa681708a45f5c7c4e215ceb66e011a2e9f0040713f18aefdb866d53cf76cab2868a39b9f7840edce4fef5a82be67335c77a6068e04112754f27ccf4e"},
+		{"Truncated output", sha512.New, "password", "salt", 2, 20, "e1d9c16aa681708a45f5c7c4e215ceb66e011a2e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := pbkdf2Key([]byte(tt.password), []byte(tt.salt), tt.iterations, tt.keyLen, tt.hash)
+			if got := hex.EncodeToString(key); got != tt.expected {
+				t.Errorf("pbkdf2Key() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Derivation scheme names accepted by LookupDerivationScheme and
+// WalletConfig.Scheme
+const (
+	SchemeBIP44        = "bip44"
+	SchemeLedgerLive   = "ledger-live"
+	SchemeLedgerLegacy = "ledger-legacy"
+	SchemeMEW          = "mew"
+)
+
+// ErrUnknownScheme is returned when a derivation scheme name is not registered
+var ErrUnknownScheme = errors.New("unknown derivation scheme")
+
+// DerivationScheme describes how a wallet lays out its accounts in the BIP-32
+// tree. Different wallets place the account index at different path levels,
+// so the same mnemonic yields different addresses under each scheme.
+type DerivationScheme struct {
+	// Name is the registry key, e.g. "ledger-live"
+	Name string
+	// Description names the wallets that use the layout
+	Description string
+	// Template shows the layout with the account index as "x"
+	Template string
+
+	path func(index uint32) DerivationPath
+}
+
+// Path returns the derivation path of the account at index. It returns
+// ErrInvalidPath if index is not below HardenedKeyStart, where the account
+// level would wrap around or turn into a hardened index.
+func (s DerivationScheme) Path(index uint32) (DerivationPath, error) {
+	if index >= HardenedKeyStart {
+		return nil, fmt.Errorf("%w: account index %d is out of range", ErrInvalidPath, index)
+	}
+	return s.path(index), nil
+}
+
+// Iterator returns a function yielding the paths of consecutive accounts
+// starting at index start. Each call returns a fresh slice, and nil once the
+// index reaches HardenedKeyStart.
+func (s DerivationScheme) Iterator(start uint32) func() DerivationPath {
+	next := start
+	return func() DerivationPath {
+		path, err := s.Path(next)
+		if err != nil {
+			return nil
+		}
+		next++
+		return path
+	}
+}
+
+// derivationSchemes is the registry of known layouts keyed by name
+var derivationSchemes = map[string]DerivationScheme{
+	SchemeBIP44: {
+		Name:        SchemeBIP44,
+		Description: "BIP-44 address index (MetaMask, Trezor, Ledger ETH app default)",
+		Template:    "m/44'/60'/0'/0/x",
+		path: func(index uint32) DerivationPath {
+			return DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, index}
+		},
+	},
+	SchemeLedgerLive: {
+		Name:        SchemeLedgerLive,
+		Description: "hardened account index (Ledger Live)",
+		Template:    "m/44'/60'/x'/0/0",
+		path: func(index uint32) DerivationPath {
+			return DerivationPath{0x8000002C, 0x8000003C, HardenedKeyStart + index, 0, 0}
+		},
+	},
+	SchemeLedgerLegacy: {
+		Name:        SchemeLedgerLegacy,
+		Description: "four-level legacy layout (Ledger Chrome app, MEW/MyCrypto Ledger)",
+		Template:    "m/44'/60'/0'/x",
+		path: func(index uint32) DerivationPath {
+			return DerivationPath{0x8000002C, 0x8000003C, 0x80000000, index}
+		},
+	},
+	SchemeMEW: {
+		Name:        SchemeMEW,
+		Description: "MEW/MyCrypto software wallets, same layout as bip44",
+		Template:    "m/44'/60'/0'/0/x",
+		path: func(index uint32) DerivationPath {
+			return DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, index}
+		},
+	},
+}
+
+// LookupDerivationScheme returns the registered scheme with the given name.
+// Names are case-insensitive and the empty name selects SchemeBIP44.
+func LookupDerivationScheme(name string) (DerivationScheme, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = SchemeBIP44
+	}
+
+	scheme, exists := derivationSchemes[name]
+	if !exists {
+		return DerivationScheme{}, fmt.Errorf("%w: %q", ErrUnknownScheme, name)
+	}
+	return scheme, nil
+}
+
+// DerivationSchemes returns all registered schemes sorted by name
+func DerivationSchemes() []DerivationScheme {
+	schemes := make([]DerivationScheme, 0, len(derivationSchemes))
+	for _, scheme := range derivationSchemes {
+		schemes = append(schemes, scheme)
+	}
+	sort.Slice(schemes, func(i, j int) bool {
+		return schemes[i].Name < schemes[j].Name
+	})
+	return schemes
+}
@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDerivationSchemePaths(t *testing.T) {
+	tests := []struct {
+		scheme string
+		index  uint32
+		want   string
+	}{
+		{SchemeBIP44, 0, "m/44'/60'/0'/0/0"},
+		{SchemeBIP44, 7, "m/44'/60'/0'/0/7"},
+		{SchemeLedgerLive, 0, "m/44'/60'/0'/0/0"},
+		{SchemeLedgerLive, 3, "m/44'/60'/3'/0/0"},
+		{SchemeLedgerLegacy, 0, "m/44'/60'/0'/0"},
+		{SchemeLedgerLegacy, 5, "m/44'/60'/0'/5"},
+		{SchemeMEW, 2, "m/44'/60'/0'/0/2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			scheme, err := LookupDerivationScheme(tt.scheme)
+			if err != nil {
+				t.Fatalf("LookupDerivationScheme(%q) failed: %v", tt.scheme, err)
+			}
+			path, err := scheme.Path(tt.index)
+			if err != nil {
+				t.Fatalf("Path(%d) failed: %v", tt.index, err)
+			}
+			if got := path.String(); got != tt.want {
+				t.Errorf("Path(%d) = %s, want %s", tt.index, got, tt.want)
+			}
+			if _, err := scheme.Path(HardenedKeyStart); !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Path(HardenedKeyStart) error = %v, want ErrInvalidPath", err)
+			}
+		})
+	}
+}
+
+func TestDerivationSchemeIterator(t *testing.T) {
+	scheme, err := LookupDerivationScheme(SchemeLedgerLive)
+	if err != nil {
+		t.Fatalf("LookupDerivationScheme failed: %v", err)
+	}
+
+	next := scheme.Iterator(1)
+	want := []string{"m/44'/60'/1'/0/0", "m/44'/60'/2'/0/0", "m/44'/60'/3'/0/0"}
+
+	var previous DerivationPath
+	for i, path := range want {
+		got := next()
+		if got.String() != path {
+			t.Errorf("Iteration %d = %s, want %s", i, got, path)
+		}
+		if previous != nil && &previous[0] == &got[0] {
+			t.Errorf("Iteration %d reused the previous slice", i)
+		}
+		previous = got
+	}
+
+	// The iterator stops before the index would wrap around
+	last := scheme.Iterator(HardenedKeyStart - 1)
+	if got := last(); got.String() != "m/44'/60'/2147483647'/0/0" {
+		t.Errorf("Last path = %s", got)
+	}
+	if got := last(); got != nil {
+		t.Errorf("Path past the last index = %s, want nil", got)
+	}
+}
+
+func TestLookupDerivationScheme(t *testing.T) {
+	scheme, err := LookupDerivationScheme("")
+	if err != nil || scheme.Name != SchemeBIP44 {
+		t.Errorf("Empty name = %q, %v; want %q", scheme.Name, err, SchemeBIP44)
+	}
+
+	scheme, err = LookupDerivationScheme(" Ledger-Live ")
+	if err != nil || scheme.Name != SchemeLedgerLive {
+		t.Errorf("Mixed case name = %q, %v; want %q", scheme.Name, err, SchemeLedgerLive)
+	}
+
+	if _, err := LookupDerivationScheme("electrum"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("Expected ErrUnknownScheme, got %v", err)
+	}
+
+	schemes := DerivationSchemes()
+	if len(schemes) != 4 {
+		t.Fatalf("DerivationSchemes() returned %d schemes, want 4", len(schemes))
+	}
+	for i := 1; i < len(schemes); i++ {
+		if schemes[i-1].Name >= schemes[i].Name {
+			t.Errorf("Schemes not sorted: %q before %q", schemes[i-1].Name, schemes[i].Name)
+		}
+	}
+
+	// The default base path is the first BIP-44 account
+	bip44, _ := LookupDerivationScheme(SchemeBIP44)
+	if got, _ := bip44.Path(0); got.String() != DefaultBaseDerivationPath.String() {
+		t.Errorf("BIP-44 index 0 = %s, want %s", got, DefaultBaseDerivationPath)
+	}
+}
+
+func TestWalletScheme(t *testing.T) {
+	if _, err := NewFromMnemonic(demoMnemonic, &WalletConfig{Scheme: "unknown"}); !errors.Is(err, ErrUnknownScheme) {
+		t.Fatalf("Expected ErrUnknownScheme, got %v", err)
+	}
+
+	wallet, err := NewFromMnemonic(demoMnemonic, &WalletConfig{Scheme: SchemeLedgerLive})
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	if wallet.Scheme().Name != SchemeLedgerLive {
+		t.Errorf("Scheme() = %q, want %q", wallet.Scheme().Name, SchemeLedgerLive)
+	}
+
+	account, err := wallet.Derive(2)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	if account.Path != "m/44'/60'/2'/0/0" {
+		t.Errorf("Account.Path = %s, want m/44'/60'/2'/0/0", account.Path)
+	}
+	if account.Index != 2 {
+		t.Errorf("Account.Index = %d, want 2", account.Index)
+	}
+
+	byPath, err := wallet.DeriveAtPathString("m/44'/60'/2'/0/0")
+	if err != nil {
+		t.Fatalf("Failed to derive account at path: %v", err)
+	}
+	if byPath.Address != account.Address {
+		t.Errorf("Derive(2) = %s, DeriveAtPath = %s", account.Address.Hex(), byPath.Address.Hex())
+	}
+
+	// Index 0 of Ledger Live coincides with the BIP-44 default account
+	first, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	if got := first.Address.Hex(); got != "0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947" {
+		t.Errorf("Ledger Live index 0 = %s, want 0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947", got)
+	}
+
+	// Index 2^31 would wrap around to Ledger Live account 0
+	if _, err := wallet.Derive(HardenedKeyStart); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("Derive(HardenedKeyStart): expected ErrInvalidPath, got %v", err)
+	}
+	if _, err := wallet.Derive(^uint32(0)); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("Derive(2^32-1): expected ErrInvalidPath, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrInvalidScryptParams is returned for scrypt cost parameters outside the
+// limits of RFC 7914 or needing more memory than scryptKey allows
+var ErrInvalidScryptParams = errors.New("invalid scrypt parameters")
+
+// scryptKey derives a key of keyLen bytes from password and salt using scrypt
+// (RFC 7914) with CPU/memory cost n, block size r and parallelization p. The
+// memory used is 128*r*(n+p) bytes; each of the two buffers is limited to
+// 1 GiB.
+func scryptKey(password, salt []byte, n, r, p, keyLen int) ([]byte, error) {
+	if n <= 1 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("%w: N must be a power of two above 1, got %d", ErrInvalidScryptParams, n)
+	}
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= 1<<30 {
+		return nil, fmt.Errorf("%w: r=%d p=%d", ErrInvalidScryptParams, r, p)
+	}
+	// B takes 128*r*p bytes and V 128*r*N
+	if r > scryptMaxBuffer/128/p || n > scryptMaxBuffer/128/r {
+		return nil, fmt.Errorf("%w: N=%d r=%d p=%d is too large", ErrInvalidScryptParams, n, r, p)
+	}
+
+	// B = PBKDF2-HMAC-SHA256(P, S, 1, p * 128 * r)
+	blockLen := 128 * r
+	b := pbkdf2Key(password, salt, 1, p*blockLen, sha256.New)
+	defer secureClear(b)
+
+	x := make([]uint32, 32*r)
+	v := make([]uint32, 32*r*n)
+	y := make([]uint32, 32*r)
+	defer clearWords(x)
+	defer clearWords(v)
+	defer clearWords(y)
+
+	for i := 0; i < p; i++ {
+		scryptROMix(b[i*blockLen:(i+1)*blockLen], x, v, y, n, r)
+	}
+
+	return pbkdf2Key(password, b, 1, keyLen, sha256.New), nil
+}
+
+// scryptMaxBuffer bounds each scrypt allocation, which also keeps the sizes
+// in range of a 32-bit int
+const scryptMaxBuffer = 1 << 30
+
+// scryptROMix mixes the 128*r byte block in place (RFC 7914, section 5)
+// using v as the n-entry scratch table
+func scryptROMix(block []byte, x, v, y []uint32, n, r int) {
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[i*4:])
+	}
+
+	words := 32 * r
+	for i := 0; i < n; i++ {
+		copy(v[i*words:], x)
+		scryptBlockMix(x, y, r)
+	}
+	for i := 0; i < n; i++ {
+		// Integerify: the first word of the last 64-byte block, modulo n
+		j := int(x[(2*r-1)*16] & uint32(n-1))
+		for k := range x {
+			x[k] ^= v[j*words+k]
+		}
+		scryptBlockMix(x, y, r)
+	}
+
+	for i, word := range x {
+		binary.LittleEndian.PutUint32(block[i*4:], word)
+	}
+}
+
+// scryptBlockMix applies BlockMix with Salsa20/8 (RFC 7914, section 4) to
+// the 2*r 64-byte blocks of b, using y as scratch space
+func scryptBlockMix(b, y []uint32, r int) {
+	var t [16]uint32
+	copy(t[:], b[(2*r-1)*16:])
+
+	// Y_i = Salsa(T xor B_i); even blocks go to the first half of the
+	// output and odd blocks to the second
+	for i := 0; i < 2*r; i++ {
+		for k := range t {
+			t[k] ^= b[i*16+k]
+		}
+		salsa208(&t)
+		copy(y[(i/2+(i&1)*r)*16:], t[:])
+	}
+	copy(b, y)
+}
+
+// salsa208 applies the Salsa20/8 core (RFC 7914, section 3) to b in place
+func salsa208(b *[16]uint32) {
+	x := *b
+	for i := 0; i < 8; i += 2 {
+		// Column round
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		// Row round
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range b {
+		b[i] += x[i]
+	}
+}
+
+// clearWords overwrites a word slice with zeros
+func clearWords(words []uint32) {
+	for i := range words {
+		words[i] = 0
+	}
+}
@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestScryptKey(t *testing.T) {
+	// RFC 7914, section 12
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		n, r, p  int
+		expected string
+	}{
+		{"Empty", "", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"NaCl", "password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+		{"SodiumChloride", "pleaseletmein", "SodiumChloride", 16384, 8, 1, "7023bdcb3afd7348461c06cd81fd38ebfda8fbba904f8e3ea9b543f6545da1f2d5432955613f0fcf62d49705242a9af9e61e85dc0d651e40dfcf017b45575887"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := scryptKey([]byte(tt.password), []byte(tt.salt), tt.n, tt.r, tt.p, 64)
+			if err != nil {
+				t.Fatalf("scryptKey() error = %v", err)
+			}
+			if got := hex.EncodeToString(key); got != tt.expected {
+				t.Errorf("scryptKey() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScryptKeyInvalidParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		n, r, p int
+	}{
+		{"N not a power of two", 1000, 8, 1},
+		{"N of 1", 1, 8, 1},
+		{"Zero r", 16, 0, 1},
+		{"Zero p", 16, 8, 0},
+		{"r*p too large", 16, 1 << 15, 1 << 15},
+		{"B too large", 16, 8, 1 << 21},
+		{"V too large", 1 << 21, 8, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scryptKey(nil, nil, tt.n, tt.r, tt.p, 32); !errors.Is(err, ErrInvalidScryptParams) {
+				t.Errorf("scryptKey() error = %v, want ErrInvalidScryptParams", err)
+			}
+		})
+	}
+}
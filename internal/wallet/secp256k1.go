@@ -0,0 +1,496 @@
+package wallet
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// This file implements the secp256k1 curve used by Ethereum. The standard
+// library only ships NIST curves, and the generic elliptic.CurveParams
+// arithmetic assumes a = -3, so field and point arithmetic are provided here.
+//
+// Field elements use four 64-bit limbs and all arithmetic on them, as well as
+// scalar multiplication, runs in constant time with respect to secret inputs.
+// Points use projective coordinates with the complete addition formulas of
+// Renes, Costello and Batina (https://eprint.iacr.org/2015/1060, algorithms 7
+// and 9), so the same instruction sequence handles doubling and infinity.
+
+// secp256k1 domain parameters (SEC 2, section 2.4.1)
+var (
+	secp256k1P, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+	secp256k1N, _  = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	secp256k1B     = big.NewInt(7)
+	secp256k1Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+	secp256k1Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+)
+
+// errInvalidPublicKey is returned when a serialized point is not on the curve
+var errInvalidPublicKey = errors.New("invalid secp256k1 public key")
+
+// fieldElement is an integer modulo p in little-endian 64-bit limbs, always
+// kept fully reduced
+type fieldElement [4]uint64
+
+// p = 2^256 - fieldC, so 2^256 ≡ fieldC (mod p)
+const fieldC = 0x1000003D1
+
+var (
+	fieldP   = fieldElement{0xFFFFFFFEFFFFFC2F, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}
+	fieldOne = fieldElement{1, 0, 0, 0}
+	fieldB3  = fieldElement{21, 0, 0, 0} // 3·b
+)
+
+// feSelect sets r to a if cond is 1 and leaves it unchanged if cond is 0
+func feSelect(r, a *fieldElement, cond uint64) {
+	mask := -cond
+	for i := range r {
+		r[i] ^= mask & (r[i] ^ a[i])
+	}
+}
+
+// feReduceOnce subtracts p from r if r >= p. Requires r < 2p.
+func feReduceOnce(r *fieldElement, carry uint64) {
+	var t fieldElement
+	var borrow uint64
+	t[0], borrow = bits.Sub64(r[0], fieldP[0], 0)
+	t[1], borrow = bits.Sub64(r[1], fieldP[1], borrow)
+	t[2], borrow = bits.Sub64(r[2], fieldP[2], borrow)
+	t[3], borrow = bits.Sub64(r[3], fieldP[3], borrow)
+	// Keep the subtraction unless it borrowed without a pending carry
+	feSelect(r, &t, carry|(borrow^1))
+}
+
+// feAdd returns a + b mod p
+func feAdd(a, b *fieldElement) fieldElement {
+	var r fieldElement
+	var carry uint64
+	r[0], carry = bits.Add64(a[0], b[0], 0)
+	r[1], carry = bits.Add64(a[1], b[1], carry)
+	r[2], carry = bits.Add64(a[2], b[2], carry)
+	r[3], carry = bits.Add64(a[3], b[3], carry)
+	feReduceOnce(&r, carry)
+	return r
+}
+
+// feSub returns a - b mod p
+func feSub(a, b *fieldElement) fieldElement {
+	var r fieldElement
+	var borrow uint64
+	r[0], borrow = bits.Sub64(a[0], b[0], 0)
+	r[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	r[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	r[3], borrow = bits.Sub64(a[3], b[3], borrow)
+
+	// Add p back if the subtraction wrapped
+	mask := -borrow
+	var carry uint64
+	r[0], carry = bits.Add64(r[0], fieldP[0]&mask, 0)
+	r[1], carry = bits.Add64(r[1], fieldP[1]&mask, carry)
+	r[2], carry = bits.Add64(r[2], fieldP[2]&mask, carry)
+	r[3], _ = bits.Add64(r[3], fieldP[3]&mask, carry)
+	return r
+}
+
+// feMul returns a · b mod p
+func feMul(a, b *fieldElement) fieldElement {
+	// Schoolbook multiplication into a 512-bit product
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j] = lo
+			carry = hi
+		}
+		t[i+4] = carry
+	}
+
+	// Fold the high half: t = lo + hi·fieldC, leaving at most 290 bits
+	var r fieldElement
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(t[4+i], fieldC)
+		var c uint64
+		lo, c = bits.Add64(lo, t[i], 0)
+		hi += c
+		lo, c = bits.Add64(lo, carry, 0)
+		hi += c
+		r[i] = lo
+		carry = hi
+	}
+
+	// Fold the remaining top limb, then any final carry out of bit 256
+	hi, lo := bits.Mul64(carry, fieldC)
+	var c uint64
+	r[0], c = bits.Add64(r[0], lo, 0)
+	r[1], c = bits.Add64(r[1], hi, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+
+	r[0], c = bits.Add64(r[0], c*fieldC, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+
+	feReduceOnce(&r, c)
+	return r
+}
+
+// feSquare returns a² mod p
+func feSquare(a *fieldElement) fieldElement {
+	return feMul(a, a)
+}
+
+// fePow returns a^e mod p. The exponent is public; the base may be secret.
+func fePow(a *fieldElement, e *fieldElement) fieldElement {
+	r := fieldOne
+	for i := 3; i >= 0; i-- {
+		for bit := 63; bit >= 0; bit-- {
+			r = feSquare(&r)
+			if (e[i]>>uint(bit))&1 == 1 {
+				r = feMul(&r, a)
+			}
+		}
+	}
+	return r
+}
+
+// feInvert returns a⁻¹ mod p using Fermat's little theorem (a^(p-2)).
+// The inverse of zero is zero.
+func feInvert(a *fieldElement) fieldElement {
+	e := fieldP
+	e[0] -= 2
+	return fePow(a, &e)
+}
+
+// feIsZeroWord returns 1 if w is zero and 0 otherwise, in constant time
+func feIsZeroWord(w uint64) uint64 {
+	return 1 ^ ((w | -w) >> 63)
+}
+
+// feSetBytes decodes a 32-byte big-endian value; ok is false if it is not below p
+func feSetBytes(b []byte) (fieldElement, bool) {
+	var r fieldElement
+	for i := 0; i < 4; i++ {
+		r[i] = binary.BigEndian.Uint64(b[24-8*i:])
+	}
+	var borrow uint64
+	_, borrow = bits.Sub64(r[0], fieldP[0], 0)
+	_, borrow = bits.Sub64(r[1], fieldP[1], borrow)
+	_, borrow = bits.Sub64(r[2], fieldP[2], borrow)
+	_, borrow = bits.Sub64(r[3], fieldP[3], borrow)
+	return r, borrow == 1
+}
+
+// feBytes encodes a as 32 big-endian bytes
+func feBytes(a *fieldElement) []byte {
+	out := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.BigEndian.PutUint64(out[24-8*i:], a[i])
+	}
+	return out
+}
+
+// feFromBig converts a big.Int in [0, p) to a field element
+func feFromBig(x *big.Int) fieldElement {
+	r, _ := feSetBytes(x.FillBytes(make([]byte, 32)))
+	return r
+}
+
+// feToBig converts a field element to a big.Int
+func feToBig(a *fieldElement) *big.Int {
+	return new(big.Int).SetBytes(feBytes(a))
+}
+
+// curvePoint is a point in projective coordinates (X:Y:Z) with x = X/Z and
+// y = Y/Z; the point at infinity is (0:1:0)
+type curvePoint struct {
+	x, y, z fieldElement
+}
+
+// newIdentityPoint returns the point at infinity
+func newIdentityPoint() curvePoint {
+	return curvePoint{y: fieldOne}
+}
+
+// pointFromAffine converts affine coordinates, mapping (0, 0) to infinity
+func pointFromAffine(x, y *big.Int) curvePoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return newIdentityPoint()
+	}
+	return curvePoint{x: feFromBig(x), y: feFromBig(y), z: fieldOne}
+}
+
+// affine converts the point to affine coordinates, mapping infinity to (0, 0)
+func (p *curvePoint) affine() (*big.Int, *big.Int) {
+	zInv := feInvert(&p.z)
+	x := feMul(&p.x, &zInv)
+	y := feMul(&p.y, &zInv)
+	return feToBig(&x), feToBig(&y)
+}
+
+// pointAdd returns p + q using the complete addition formula for a = 0
+func pointAdd(p, q *curvePoint) curvePoint {
+	t0 := feMul(&p.x, &q.x)
+	t1 := feMul(&p.y, &q.y)
+	t2 := feMul(&p.z, &q.z)
+	t3 := feAdd(&p.x, &p.y)
+	t4 := feAdd(&q.x, &q.y)
+	t3 = feMul(&t3, &t4)
+	t4 = feAdd(&t0, &t1)
+	t3 = feSub(&t3, &t4)
+	t4 = feAdd(&p.y, &p.z)
+	x3 := feAdd(&q.y, &q.z)
+	t4 = feMul(&t4, &x3)
+	x3 = feAdd(&t1, &t2)
+	t4 = feSub(&t4, &x3)
+	x3 = feAdd(&p.x, &p.z)
+	y3 := feAdd(&q.x, &q.z)
+	x3 = feMul(&x3, &y3)
+	y3 = feAdd(&t0, &t2)
+	y3 = feSub(&x3, &y3)
+	x3 = feAdd(&t0, &t0)
+	t0 = feAdd(&x3, &t0)
+	t2 = feMul(&fieldB3, &t2)
+	z3 := feAdd(&t1, &t2)
+	t1 = feSub(&t1, &t2)
+	y3 = feMul(&fieldB3, &y3)
+	x3 = feMul(&t4, &y3)
+	t2 = feMul(&t3, &t1)
+	x3 = feSub(&t2, &x3)
+	y3 = feMul(&y3, &t0)
+	t1 = feMul(&t1, &z3)
+	y3 = feAdd(&t1, &y3)
+	t0 = feMul(&t0, &t3)
+	z3 = feMul(&z3, &t4)
+	z3 = feAdd(&z3, &t0)
+	return curvePoint{x: x3, y: y3, z: z3}
+}
+
+// pointDouble returns 2p using the complete doubling formula for a = 0
+func pointDouble(p *curvePoint) curvePoint {
+	t0 := feSquare(&p.y)
+	z3 := feAdd(&t0, &t0)
+	z3 = feAdd(&z3, &z3)
+	z3 = feAdd(&z3, &z3)
+	t1 := feMul(&p.y, &p.z)
+	t2 := feSquare(&p.z)
+	t2 = feMul(&fieldB3, &t2)
+	x3 := feMul(&t2, &z3)
+	y3 := feAdd(&t0, &t2)
+	z3 = feMul(&t1, &z3)
+	t1 = feAdd(&t2, &t2)
+	t2 = feAdd(&t1, &t2)
+	t0 = feSub(&t0, &t2)
+	y3 = feMul(&t0, &y3)
+	y3 = feAdd(&x3, &y3)
+	t1 = feMul(&p.x, &p.y)
+	x3 = feMul(&t0, &t1)
+	x3 = feAdd(&x3, &x3)
+	return curvePoint{x: x3, y: y3, z: z3}
+}
+
+// pointSelect sets r to p if cond is 1 and leaves it unchanged if cond is 0
+func pointSelect(r, p *curvePoint, cond uint64) {
+	feSelect(&r.x, &p.x, cond)
+	feSelect(&r.y, &p.y, cond)
+	feSelect(&r.z, &p.z, cond)
+}
+
+// pointScalarMult returns k·p for a 32-byte big-endian scalar using a fixed
+// 4-bit window. Every window performs the same doublings, a full table scan
+// and one addition, so the running time does not depend on k.
+func pointScalarMult(p *curvePoint, k *[32]byte) curvePoint {
+	var table [16]curvePoint
+	table[0] = newIdentityPoint()
+	table[1] = *p
+	for i := 2; i < 16; i++ {
+		table[i] = pointAdd(&table[i-1], p)
+	}
+
+	r := newIdentityPoint()
+	for i := 0; i < 64; i++ {
+		if i > 0 {
+			r = pointDouble(&r)
+			r = pointDouble(&r)
+			r = pointDouble(&r)
+			r = pointDouble(&r)
+		}
+
+		window := uint64(k[i/2] >> (4 * uint(1-i%2)) & 0x0F)
+		selected := newIdentityPoint()
+		for j := uint64(1); j < 16; j++ {
+			pointSelect(&selected, &table[j], feIsZeroWord(window^j))
+		}
+		r = pointAdd(&r, &selected)
+	}
+	return r
+}
+
+// secp256k1Curve implements elliptic.Curve for secp256k1
+type secp256k1Curve struct {
+	params *elliptic.CurveParams
+}
+
+var (
+	secp256k1Once     sync.Once
+	secp256k1Instance *secp256k1Curve
+)
+
+// Secp256k1 returns the secp256k1 curve used by Ethereum and Bitcoin.
+//
+// The returned curve's Params must not be used with the generic
+// elliptic.CurveParams methods, which assume a = -3; use the curve's own
+// methods instead.
+func Secp256k1() elliptic.Curve {
+	secp256k1Once.Do(func() {
+		secp256k1Instance = &secp256k1Curve{params: &elliptic.CurveParams{
+			P:       secp256k1P,
+			N:       secp256k1N,
+			B:       secp256k1B,
+			Gx:      secp256k1Gx,
+			Gy:      secp256k1Gy,
+			BitSize: 256,
+			Name:    "secp256k1",
+		}}
+	})
+	return secp256k1Instance
+}
+
+// Params returns the curve parameters
+func (c *secp256k1Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// IsOnCurve reports whether (x, y) satisfies y² = x³ + 7 mod p
+func (c *secp256k1Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(secp256k1P) >= 0 || y.Sign() < 0 || y.Cmp(secp256k1P) >= 0 {
+		return false
+	}
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, secp256k1P)
+	return lhs.Cmp(secp256k1Polynomial(x)) == 0
+}
+
+// Add returns the sum of (x1, y1) and (x2, y2)
+func (c *secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := pointFromAffine(x1, y1)
+	q := pointFromAffine(x2, y2)
+	r := pointAdd(&p, &q)
+	return r.affine()
+}
+
+// Double returns 2·(x1, y1)
+func (c *secp256k1Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	p := pointFromAffine(x1, y1)
+	r := pointDouble(&p)
+	return r.affine()
+}
+
+// ScalarMult returns k·(x1, y1) where k is a big-endian integer
+func (c *secp256k1Curve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	scalar := secp256k1Scalar(k)
+	p := pointFromAffine(x1, y1)
+	r := pointScalarMult(&p, &scalar)
+	secureClear(scalar[:])
+	return r.affine()
+}
+
+// ScalarBaseMult returns k·G where k is a big-endian integer
+func (c *secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(secp256k1Gx, secp256k1Gy, k)
+}
+
+// secp256k1Scalar converts a big-endian scalar to 32 bytes, reducing
+// oversized inputs modulo n
+func secp256k1Scalar(k []byte) [32]byte {
+	var scalar [32]byte
+	if len(k) > 32 {
+		reduced := new(big.Int).SetBytes(k)
+		reduced.Mod(reduced, secp256k1N)
+		reduced.FillBytes(scalar[:])
+		reduced.SetInt64(0)
+		return scalar
+	}
+	copy(scalar[32-len(k):], k)
+	return scalar
+}
+
+// secp256k1Polynomial returns x³ + 7 mod p
+func secp256k1Polynomial(x *big.Int) *big.Int {
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, secp256k1B)
+	return rhs.Mod(rhs, secp256k1P)
+}
+
+// secp256k1Add returns the sum of two affine points
+func secp256k1Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return Secp256k1().Add(x1, y1, x2, y2)
+}
+
+// secp256k1ScalarBaseMult returns k·G
+func secp256k1ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return Secp256k1().ScalarBaseMult(k)
+}
+
+// secp256k1Compress serializes a point in 33-byte SEC 1 compressed form
+func secp256k1Compress(x, y *big.Int) []byte {
+	out := make([]byte, 33)
+	out[0] = 0x02 | byte(y.Bit(0))
+	x.FillBytes(out[1:])
+	return out
+}
+
+// secp256k1Decompress parses a 33-byte SEC 1 compressed point
+func secp256k1Decompress(data []byte) (*big.Int, *big.Int, error) {
+	if len(data) != 33 || (data[0] != 0x02 && data[0] != 0x03) {
+		return nil, nil, errInvalidPublicKey
+	}
+
+	x := new(big.Int).SetBytes(data[1:])
+	if x.Cmp(secp256k1P) >= 0 {
+		return nil, nil, errInvalidPublicKey
+	}
+
+	// Recover y from the curve equation and pick the root with the encoded parity
+	y := new(big.Int).ModSqrt(secp256k1Polynomial(x), secp256k1P)
+	if y == nil {
+		return nil, nil, errInvalidPublicKey
+	}
+	if y.Bit(0) != uint(data[0]&1) {
+		y.Sub(secp256k1P, y)
+	}
+	return x, y, nil
+}
+
+// secp256k1Marshal serializes a point in 65-byte SEC 1 uncompressed form
+func secp256k1Marshal(x, y *big.Int) []byte {
+	out := make([]byte, 65)
+	out[0] = 0x04
+	x.FillBytes(out[1:33])
+	y.FillBytes(out[33:])
+	return out
+}
+
+// secp256k1Unmarshal parses a 65-byte SEC 1 uncompressed point
+func secp256k1Unmarshal(data []byte) (*big.Int, *big.Int, error) {
+	if len(data) != 65 || data[0] != 0x04 {
+		return nil, nil, errInvalidPublicKey
+	}
+	x := new(big.Int).SetBytes(data[1:33])
+	y := new(big.Int).SetBytes(data[33:])
+	if !Secp256k1().IsOnCurve(x, y) {
+		return nil, nil, errInvalidPublicKey
+	}
+	return x, y, nil
+}
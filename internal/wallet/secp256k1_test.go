@@ -0,0 +1,199 @@
+package wallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func hexToBig(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("bad test hex %q", s)
+	}
+	return n
+}
+
+func TestSecp256k1ScalarBaseMult(t *testing.T) {
+	tests := []struct {
+		k string
+		x string
+		y string
+	}{
+		{"01", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+		{"02", "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+		{"03", "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9", "388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", "b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777"},
+		{"ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb", "4da006f958beba78ec54443df4a3f52237253f7ae8cbdb17dccf3feaa57f3126", "da0a0909f11998130c2d0e86a485f4e79ee466a183a476c432c68758ab9e630b"},
+		{"3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d", "10c283aac7b35b4ae6fab201d36e8322c3408331149982e16013a5bcb917081c", "e524905eae685ebf6dcc0361402ced1b7aba0bfdac66b52a4ed8ef0493d89675"},
+		{"2e7d2c03a9507ae265ecf5b5356885a53393a2029d241394997265a1a25aefc6", "92a762e0123945455b7afe675e5ab98fb1586de43e5682514b9454d6edced724", "357ebd928cf9e425085c4acf5575d41b69952cfdabd982967ab248b2870c3c57"},
+		{"18ac3e7343f016890c510e93f935261169d9e3f565436429830faf0934f4f8e4", "1ecb763a2589f189fa66df754d4dc27aeb1ae6a23d3e88e536beb8f57852ef5c", "0fc748cd9d366016bb0fd6789240c6ff9e643cfd2606a6eb2e09065824bbf340"},
+	}
+
+	curve := Secp256k1()
+	for _, tt := range tests {
+		k, _ := hex.DecodeString(tt.k)
+		x, y := curve.ScalarBaseMult(k)
+		if x.Cmp(hexToBig(t, tt.x)) != 0 || y.Cmp(hexToBig(t, tt.y)) != 0 {
+			t.Errorf("ScalarBaseMult(%s) = (%x, %x), want (%s, %s)", tt.k, x, y, tt.x, tt.y)
+		}
+		if !curve.IsOnCurve(x, y) {
+			t.Errorf("ScalarBaseMult(%s) is not on the curve", tt.k)
+		}
+	}
+}
+
+func TestSecp256k1ScalarMult(t *testing.T) {
+	curve := Secp256k1()
+	px := hexToBig(t, "adafa1c0c3a52e3da24bb7c22e38582bf907eef30d2bd126948a5592079336b1")
+	py := hexToBig(t, "7a4d5dd2ea9a7e388d94201ef1933282b45e1911a9188ef589fe78dbb0df7284")
+	k := sha256.Sum256([]byte("scalar"))
+
+	x, y := curve.ScalarMult(px, py, k[:])
+	if x.Cmp(hexToBig(t, "3a43c56c750eff45153709732fce1b7aeb65cb27b6da3c1dc8e4bed8f1f58aa5")) != 0 ||
+		y.Cmp(hexToBig(t, "ec00dcbb3fb3b2e6ff8c78b93120d12dfc0123ce34e1a3a8f15cb07d747e981a")) != 0 {
+		t.Errorf("ScalarMult() = (%x, %x)", x, y)
+	}
+
+	// Multiplying by n or zero yields the point at infinity, encoded as (0, 0)
+	for _, scalar := range [][]byte{secp256k1N.Bytes(), {0}, nil} {
+		x, y := curve.ScalarMult(px, py, scalar)
+		if x.Sign() != 0 || y.Sign() != 0 {
+			t.Errorf("ScalarMult(%x) = (%x, %x), want infinity", scalar, x, y)
+		}
+	}
+
+	// Oversized scalars are reduced modulo n
+	oversized := append(append([]byte{}, secp256k1N.Bytes()...), 0)
+	oversized = new(big.Int).Add(new(big.Int).SetBytes(oversized), big.NewInt(2)).Bytes()
+	x, y = curve.ScalarBaseMult(oversized)
+	x2, y2 := curve.ScalarBaseMult([]byte{2})
+	if x.Cmp(x2) != 0 || y.Cmp(y2) != 0 {
+		t.Errorf("ScalarBaseMult(256n+2) != ScalarBaseMult(2)")
+	}
+}
+
+func TestSecp256k1AddDouble(t *testing.T) {
+	curve := Secp256k1()
+	gx, gy := curve.Params().Gx, curve.Params().Gy
+
+	// G + G == 2G == Double(G)
+	ax, ay := curve.Add(gx, gy, gx, gy)
+	dx, dy := curve.Double(gx, gy)
+	bx, by := curve.ScalarBaseMult([]byte{2})
+	if ax.Cmp(bx) != 0 || ay.Cmp(by) != 0 || dx.Cmp(bx) != 0 || dy.Cmp(by) != 0 {
+		t.Errorf("G + G, Double(G) and 2G disagree")
+	}
+
+	// 2G + G == 3G
+	sx, sy := curve.Add(bx, by, gx, gy)
+	tx, ty := curve.ScalarBaseMult([]byte{3})
+	if sx.Cmp(tx) != 0 || sy.Cmp(ty) != 0 {
+		t.Errorf("2G + G != 3G")
+	}
+
+	// G + (-G) is infinity, and infinity is the identity
+	negY := new(big.Int).Sub(secp256k1P, gy)
+	ix, iy := curve.Add(gx, gy, gx, negY)
+	if ix.Sign() != 0 || iy.Sign() != 0 {
+		t.Errorf("G + (-G) = (%x, %x), want infinity", ix, iy)
+	}
+	ix, iy = curve.Add(new(big.Int), new(big.Int), gx, gy)
+	if ix.Cmp(gx) != 0 || iy.Cmp(gy) != 0 {
+		t.Errorf("infinity + G != G")
+	}
+}
+
+func TestSecp256k1IsOnCurve(t *testing.T) {
+	curve := Secp256k1()
+	gx, gy := curve.Params().Gx, curve.Params().Gy
+	if !curve.IsOnCurve(gx, gy) {
+		t.Errorf("generator is not on the curve")
+	}
+	if curve.IsOnCurve(gx, new(big.Int).Add(gy, big.NewInt(1))) {
+		t.Errorf("(Gx, Gy+1) reported on the curve")
+	}
+	if curve.IsOnCurve(new(big.Int).Add(gx, secp256k1P), gy) {
+		t.Errorf("unreduced coordinate reported on the curve")
+	}
+}
+
+func TestSecp256k1Encoding(t *testing.T) {
+	curve := Secp256k1()
+	for _, k := range []byte{1, 2, 3, 7} {
+		x, y := curve.ScalarBaseMult([]byte{k})
+
+		dx, dy, err := secp256k1Decompress(secp256k1Compress(x, y))
+		if err != nil || dx.Cmp(x) != 0 || dy.Cmp(y) != 0 {
+			t.Errorf("compressed round trip failed for %dG: %v", k, err)
+		}
+
+		ux, uy, err := secp256k1Unmarshal(secp256k1Marshal(x, y))
+		if err != nil || ux.Cmp(x) != 0 || uy.Cmp(y) != 0 {
+			t.Errorf("uncompressed round trip failed for %dG: %v", k, err)
+		}
+	}
+
+	// x = 7 has no matching y on the curve
+	invalid, _ := hex.DecodeString("020000000000000000000000000000000000000000000000000000000000000007")
+	if _, _, err := secp256k1Decompress(invalid); err != errInvalidPublicKey {
+		t.Errorf("secp256k1Decompress(invalid) = %v, want %v", err, errInvalidPublicKey)
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	p := secp256k1P
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(0x1000003D1),
+		new(big.Int).Sub(p, big.NewInt(1)),
+		new(big.Int).Sub(p, big.NewInt(0x1000003D1)),
+		hexToBig(t, "fffffffffffffffffffffffffffffffffffffffffffffffffffffffeffff0000"),
+		secp256k1Gx,
+		secp256k1Gy,
+	}
+
+	for _, a := range values {
+		fa := feFromBig(a)
+		for _, b := range values {
+			fb := feFromBig(b)
+
+			sum := feAdd(&fa, &fb)
+			want := new(big.Int).Add(a, b)
+			if feToBig(&sum).Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("feAdd(%x, %x) = %x, want %x", a, b, feToBig(&sum), want)
+			}
+
+			diff := feSub(&fa, &fb)
+			want = new(big.Int).Sub(a, b)
+			if feToBig(&diff).Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("feSub(%x, %x) = %x, want %x", a, b, feToBig(&diff), want)
+			}
+
+			prod := feMul(&fa, &fb)
+			want = new(big.Int).Mul(a, b)
+			if feToBig(&prod).Cmp(want.Mod(want, p)) != 0 {
+				t.Errorf("feMul(%x, %x) = %x, want %x", a, b, feToBig(&prod), want)
+			}
+		}
+
+		if a.Sign() != 0 {
+			inv := feInvert(&fa)
+			one := feMul(&fa, &inv)
+			if one != fieldOne {
+				t.Errorf("feInvert(%x) is not an inverse", a)
+			}
+		}
+	}
+}
+
+func BenchmarkSecp256k1ScalarBaseMult(b *testing.B) {
+	k := sha256.Sum256([]byte("benchmark"))
+	curve := Secp256k1()
+	for i := 0; i < b.N; i++ {
+		curve.ScalarBaseMult(k[:])
+	}
+}
@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"simple-eth-hd-wallet/internal/rlp"
+)
+
+// SetCodeAuthorizationMagic prefixes the signing payload of an EIP-7702
+// authorization
+const SetCodeAuthorizationMagic = 0x05
+
+// ErrInvalidAuthorization is returned for malformed or unsigned EIP-7702
+// authorizations
+var ErrInvalidAuthorization = errors.New("invalid authorization")
+
+// SetCodeAuthorization is an EIP-7702 authorization delegating the code of
+// the signing account to Address. A zero or nil ChainID makes the
+// authorization valid on every chain. V holds the y-parity of the signature.
+type SetCodeAuthorization struct {
+	ChainID *big.Int
+	Address Address
+	Nonce   uint64
+	V       uint8
+	R, S    *big.Int
+}
+
+// SigningHash returns the hash that the authority signs,
+// keccak256(0x05 || rlp([chain_id, address, nonce]))
+func (a *SetCodeAuthorization) SigningHash() ([]byte, error) {
+	encoded, err := rlp.EncodeToBytes([]interface{}{bigOrZero(a.ChainID), a.Address, a.Nonce})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthorization, err)
+	}
+	return keccak256([]byte{SetCodeAuthorizationMagic}, encoded), nil
+}
+
+// Authority recovers the account that signed the authorization. High-S
+// signatures are rejected as required by EIP-7702.
+func (a *SetCodeAuthorization) Authority() (Address, error) {
+	if a.R == nil || a.S == nil {
+		return Address{}, fmt.Errorf("%w: not signed", ErrInvalidAuthorization)
+	}
+	if a.V > 1 {
+		return Address{}, fmt.Errorf("%w: y-parity %d", ErrInvalidRecoveryID, a.V)
+	}
+
+	hash, err := a.SigningHash()
+	if err != nil {
+		return Address{}, err
+	}
+	sig, err := signatureBytes(a.R, a.S, a.V)
+	if err != nil {
+		return Address{}, err
+	}
+	return RecoverAddressStrict(hash, sig)
+}
+
+// SignAuthorization signs an authorization with the key of a derived account
+// and returns the signed copy. When the same account also sends the set code
+// transaction, its nonce is incremented first, so the authorization must use
+// the transaction nonce plus one.
+func (w *SimpleWallet) SignAuthorization(address Address, auth SetCodeAuthorization) (SetCodeAuthorization, error) {
+	hash, err := auth.SigningHash()
+	if err != nil {
+		return SetCodeAuthorization{}, err
+	}
+
+	sig, err := w.SignHash(address, hash)
+	if err != nil {
+		return SetCodeAuthorization{}, fmt.Errorf("failed to sign authorization: %w", err)
+	}
+
+	auth.R = new(big.Int).SetBytes(sig[:32])
+	auth.S = new(big.Int).SetBytes(sig[32:64])
+	auth.V = sig[RecoveryIDOffset]
+	return auth, nil
+}
+
+// validateAuthList checks the fields specific to set code transactions. Every
+// authorization must be signed, as the list is part of the signed payload.
+func (tx *Transaction) validateAuthList() error {
+	if tx.To == nil {
+		return fmt.Errorf("%w: set code transaction without recipient", ErrInvalidTransaction)
+	}
+	if len(tx.AuthList) == 0 {
+		return fmt.Errorf("%w: set code transaction without authorizations", ErrInvalidTransaction)
+	}
+	for i, auth := range tx.AuthList {
+		if auth.ChainID != nil && (auth.ChainID.Sign() < 0 || auth.ChainID.BitLen() > 256) {
+			return fmt.Errorf("%w: authorization %d chain ID %v is out of range", ErrInvalidTransaction, i, auth.ChainID)
+		}
+		if auth.R == nil || auth.S == nil {
+			return fmt.Errorf("%w: authorization %d is not signed", ErrInvalidTransaction, i)
+		}
+		if auth.R.Sign() < 0 || auth.S.Sign() < 0 || auth.R.BitLen() > 256 || auth.S.BitLen() > 256 {
+			return fmt.Errorf("%w: authorization %d signature value out of range", ErrInvalidTransaction, i)
+		}
+		// EIP-7702 requires low-S authorization signatures
+		if auth.S.Cmp(secp256k1HalfN) > 0 {
+			return fmt.Errorf("%w: authorization %d has a high S value", ErrInvalidTransaction, i)
+		}
+		if auth.V > 1 {
+			return fmt.Errorf("%w: authorization %d has y-parity %d", ErrInvalidTransaction, i, auth.V)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// vectorAuthorization returns the authorization of the set code vectors,
+// signed with key 0x4646…46
+func vectorAuthorization(t *testing.T) SetCodeAuthorization {
+	t.Helper()
+	auth := SetCodeAuthorization{
+		ChainID: big.NewInt(1),
+		Address: HexToAddress("0x3535353535353535353535353535353535353535"),
+		Nonce:   7,
+	}
+	hash, err := auth.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	sig, err := signHash(mustBigInt("0x4646464646464646464646464646464646464646464646464646464646464646"), hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	auth.R = new(big.Int).SetBytes(sig[:32])
+	auth.S = new(big.Int).SetBytes(sig[32:64])
+	auth.V = sig[RecoveryIDOffset]
+	return auth
+}
+
+func TestSetCodeAuthorization(t *testing.T) {
+	auth := SetCodeAuthorization{
+		ChainID: big.NewInt(1),
+		Address: HexToAddress("0x3535353535353535353535353535353535353535"),
+		Nonce:   7,
+	}
+	hash, err := auth.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "155d8ac0276a036817313c7fa22bab28775df2f2cf6845cb72cf0aafbfebd00d" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	signed := vectorAuthorization(t)
+	if signed.V != 0 {
+		t.Errorf("V = %d, want 0", signed.V)
+	}
+	if want := mustBigInt("0xada169c25b37d5ec7657b637677f2cc29cba28c40bfd0ac50bececf91263edd5"); signed.R.Cmp(want) != 0 {
+		t.Errorf("R = %x, want %x", signed.R, want)
+	}
+	if want := mustBigInt("0x56da4e2c712f3431bcb8728e66bb387b0e177ea92484d366c055c7ec6b56969a"); signed.S.Cmp(want) != 0 {
+		t.Errorf("S = %x, want %x", signed.S, want)
+	}
+	authority, err := signed.Authority()
+	if err != nil {
+		t.Fatalf("Authority failed: %v", err)
+	}
+	if want := HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"); authority != want {
+		t.Errorf("Authority = %s, want %s", authority.Hex(), want.Hex())
+	}
+
+	// Wallet accounts sign chain-independent authorizations
+	wallet, account := newTestWallet(t)
+	walletAuth, err := wallet.SignAuthorization(account.Address, SetCodeAuthorization{Address: auth.Address, Nonce: 1})
+	if err != nil {
+		t.Fatalf("SignAuthorization failed: %v", err)
+	}
+	if authority, err := walletAuth.Authority(); err != nil || authority != account.Address {
+		t.Errorf("Authority = %s, %v; want %s", authority.Hex(), err, account.Address.Hex())
+	}
+	if _, err := wallet.SignAuthorization(Address{}, auth); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestSetCodeTxVector(t *testing.T) {
+	tx := &Transaction{
+		Type:      SetCodeTxType,
+		ChainID:   big.NewInt(1),
+		Nonce:     8,
+		GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(30000000000),
+		Gas:       100000,
+		To:        addressPtr("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"),
+		AuthList:  []SetCodeAuthorization{vectorAuthorization(t)},
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "102642b73ee44d74a488eb5e42e2327f657f7a97c918acaa8135a2aef2417804" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	sig, err := signHash(mustBigInt("0x4646464646464646464646464646464646464646464646464646464646464646"), hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	want := "04f8ca0108843b9aca008506fc23ac00830186a0949d8a62f656a8d1615c1294fd71e9cfb3e4855a4f8080c0" +
+		"f85cf85a019435353535353535353535353535353535353535350780" +
+		"a0ada169c25b37d5ec7657b637677f2cc29cba28c40bfd0ac50bececf91263edd5" +
+		"a056da4e2c712f3431bcb8728e66bb387b0e177ea92484d366c055c7ec6b56969a" +
+		"80" +
+		"a0d600e7c65e9fea87b571ee40a5ed3878409025a3b201b01b7a589c45e03186e5" +
+		"a04418f7e86abdc94dcb054ba003e1b373b995a9de8f0859ae9b6a9060f0144fa0"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Errorf("MarshalBinary = %s, want %s", got, want)
+	}
+	txHash, _ := signed.Hash()
+	if got := hex.EncodeToString(txHash); got != "61e80c828a1ad9c8003a6f5562fd4a0ddfd679de9335cd9668be836b205f6983" {
+		t.Errorf("Hash = %s", got)
+	}
+
+	decoded, err := ParseRawTransaction(want)
+	if err != nil {
+		t.Fatalf("ParseRawTransaction failed: %v", err)
+	}
+	sender, err := decoded.Sender()
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if sender != *tx.To {
+		t.Errorf("Sender = %s, want %s", sender.Hex(), tx.To.Hex())
+	}
+	if len(decoded.AuthList) != 1 {
+		t.Fatalf("Decoded %d authorizations, want 1", len(decoded.AuthList))
+	}
+	if authority, err := decoded.AuthList[0].Authority(); err != nil || authority != sender {
+		t.Errorf("Authority = %s, %v; want %s", authority.Hex(), err, sender.Hex())
+	}
+}
+
+func TestSetCodeErrors(t *testing.T) {
+	auth := vectorAuthorization(t)
+
+	badParity := auth
+	badParity.V = 2
+	if _, err := badParity.Authority(); !errors.Is(err, ErrInvalidRecoveryID) {
+		t.Errorf("Expected ErrInvalidRecoveryID, got %v", err)
+	}
+	if _, err := (&SetCodeAuthorization{Nonce: 1}).Authority(); !errors.Is(err, ErrInvalidAuthorization) {
+		t.Errorf("Expected ErrInvalidAuthorization, got %v", err)
+	}
+	highS := auth
+	highS.S = new(big.Int).Sub(secp256k1N, auth.S)
+	highS.V ^= 1
+	if _, err := highS.Authority(); !errors.Is(err, ErrHighS) {
+		t.Errorf("Expected ErrHighS, got %v", err)
+	}
+
+	invalid := []*Transaction{
+		{Type: SetCodeTxType, ChainID: big.NewInt(1), AuthList: []SetCodeAuthorization{auth}},
+		{Type: SetCodeTxType, ChainID: big.NewInt(1), To: &Address{}},
+		{Type: SetCodeTxType, ChainID: big.NewInt(1), To: &Address{}, AuthList: []SetCodeAuthorization{{ChainID: big.NewInt(-1)}}},
+		{Type: SetCodeTxType, ChainID: big.NewInt(1), To: &Address{}, AuthList: []SetCodeAuthorization{auth}, GasPrice: big.NewInt(1)},
+		{Type: DynamicFeeTxType, ChainID: big.NewInt(1), AuthList: []SetCodeAuthorization{auth}},
+	}
+	unsigned := auth
+	unsigned.R, unsigned.S = nil, nil
+	noS := auth
+	noS.S = nil
+	negativeR := auth
+	negativeR.R = new(big.Int).Neg(auth.R)
+	wide := new(big.Int).Lsh(big.NewInt(1), 256)
+	wideChainID := auth
+	wideChainID.ChainID = wide
+	wideR := auth
+	wideR.R = wide
+	wideS := auth
+	wideS.S = wide
+	for _, bad := range []SetCodeAuthorization{unsigned, noS, negativeR, badParity, wideChainID, wideR, wideS, highS} {
+		invalid = append(invalid, &Transaction{Type: SetCodeTxType, ChainID: big.NewInt(1), To: &Address{}, AuthList: []SetCodeAuthorization{bad}})
+	}
+	for _, tx := range invalid {
+		if _, err := tx.SigningHash(); !errors.Is(err, ErrInvalidTransaction) {
+			t.Errorf("SigningHash(%+v) error = %v, want ErrInvalidTransaction", tx, err)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Signature layout constants
+const (
+	// HashLength is the length of a message hash accepted by SignHash
+	HashLength = 32
+	// SignatureLength is the length of a recoverable [R || S || V] signature
+	SignatureLength = 65
+	// RecoveryIDOffset is the position of V in a recoverable signature
+	RecoveryIDOffset = 64
+)
+
+// Signing and recovery errors
+var (
+	ErrInvalidHashLength      = errors.New("invalid hash length")
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+	ErrInvalidRecoveryID      = errors.New("invalid signature recovery id")
+	ErrInvalidSignature       = errors.New("invalid signature")
+	ErrHighS                  = errors.New("signature S value is not in the lower half of the order")
+	errInvalidPrivateKey      = errors.New("invalid secp256k1 private key")
+)
+
+// secp256k1HalfN is n/2, the largest S value accepted as low-S
+var secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+
+// rfc6979Nonces returns a generator of deterministic ECDSA nonces for the
+// private key x and message hash following RFC 6979 section 3.2 with
+// HMAC-SHA256. Successive calls continue the generator for the rare case
+// where a nonce yields r = 0 or s = 0.
+func rfc6979Nonces(x, hash []byte) func() *big.Int {
+	// bits2octets(h1): the hash as an integer reduced modulo n
+	h1 := new(big.Int).SetBytes(hash)
+	h1.Mod(h1, secp256k1N)
+	hashOctets := h1.FillBytes(make([]byte, 32))
+
+	v := make([]byte, sha256.Size)
+	k := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+
+	mac := func(key []byte, parts ...[]byte) []byte {
+		m := hmac.New(sha256.New, key)
+		for _, part := range parts {
+			m.Write(part)
+		}
+		return m.Sum(nil)
+	}
+
+	k = mac(k, v, []byte{0x00}, x, hashOctets)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, hashOctets)
+	v = mac(k, v)
+	secureClear(hashOctets)
+
+	first := true
+	return func() *big.Int {
+		if !first {
+			k = mac(k, v, []byte{0x00})
+			v = mac(k, v)
+		}
+		first = false
+
+		for {
+			v = mac(k, v)
+			nonce := new(big.Int).SetBytes(v)
+			if nonce.Sign() > 0 && nonce.Cmp(secp256k1N) < 0 {
+				return nonce
+			}
+			k = mac(k, v, []byte{0x00})
+			v = mac(k, v)
+		}
+	}
+}
+
+// signHash produces a recoverable [R || S || V] signature of a 32-byte hash
+// with V in {0, 1}. The nonce follows RFC 6979 and S is normalized to the
+// lower half of the group order.
+func signHash(d *big.Int, hash []byte) ([]byte, error) {
+	if len(hash) != HashLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidHashLength, len(hash))
+	}
+	if d.Sign() <= 0 || d.Cmp(secp256k1N) >= 0 {
+		return nil, errInvalidPrivateKey
+	}
+
+	x := d.FillBytes(make([]byte, 32))
+	defer secureClear(x)
+
+	z := new(big.Int).SetBytes(hash)
+	nonces := rfc6979Nonces(x, hash)
+	nMinus2 := new(big.Int).Sub(secp256k1N, big.NewInt(2))
+
+	for {
+		k := nonces()
+		kBytes := k.FillBytes(make([]byte, 32))
+		rx, ry := secp256k1ScalarBaseMult(kBytes)
+		secureClear(kBytes)
+
+		// The recovery ID records the parity of R.y and whether R.x overflowed n
+		recoveryID := byte(ry.Bit(0))
+		if rx.Cmp(secp256k1N) >= 0 {
+			recoveryID |= 2
+		}
+
+		r := new(big.Int).Mod(rx, secp256k1N)
+		if r.Sign() == 0 {
+			k.SetInt64(0)
+			continue
+		}
+
+		// s = k⁻¹(z + r·d) mod n, inverting k by Fermat's little theorem
+		kInv := new(big.Int).Exp(k, nMinus2, secp256k1N)
+		s := new(big.Int).Mul(r, d)
+		s.Add(s, z)
+		s.Mul(s, kInv)
+		s.Mod(s, secp256k1N)
+		k.SetInt64(0)
+		kInv.SetInt64(0)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		// Normalize to low-S; negating S mirrors R and flips its y parity
+		if s.Cmp(secp256k1HalfN) > 0 {
+			s.Sub(secp256k1N, s)
+			recoveryID ^= 1
+		}
+
+		sig := make([]byte, SignatureLength)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:64])
+		sig[RecoveryIDOffset] = recoveryID
+		return sig, nil
+	}
+}
+
+// SignHash signs a 32-byte hash with the private key of a derived account and
+// returns a 65-byte [R || S || V] signature with V in {0, 1}. Signing is
+// deterministic (RFC 6979) and always yields a low-S signature. It returns
+// ErrWalletLocked if the wallet is locked.
+func (w *SimpleWallet) SignHash(address Address, hash []byte) ([]byte, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.isLocked {
+		return nil, ErrWalletLocked
+	}
+
+	account, exists := w.accounts[address]
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	w.touch()
+
+	return signHash(account.PrivateKey.D, hash)
+}
+
+// recoverPublicKey recovers the public key that produced sig over hash
+// following SEC 1 section 4.1.6. V may be 0/1 or 27/28. In strict mode
+// signatures with S above n/2 are rejected as malleable.
+func recoverPublicKey(hash, sig []byte, strict bool) (*ecdsa.PublicKey, error) {
+	if len(hash) != HashLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidHashLength, len(hash))
+	}
+	if len(sig) != SignatureLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSignatureLength, len(sig))
+	}
+
+	v := sig[RecoveryIDOffset]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRecoveryID, sig[RecoveryIDOffset])
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if r.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Sign() == 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, ErrInvalidSignature
+	}
+	if strict && s.Cmp(secp256k1HalfN) > 0 {
+		return nil, ErrHighS
+	}
+
+	// R is the point with x = r and the y parity recorded in V
+	compressed := make([]byte, 33)
+	compressed[0] = 0x02 | v
+	r.FillBytes(compressed[1:])
+	rx, ry, err := secp256k1Decompress(compressed)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	// Q = r⁻¹(s·R − z·G) = (−z·r⁻¹)·G + (s·r⁻¹)·R
+	rInv := new(big.Int).ModInverse(r, secp256k1N)
+	u1 := new(big.Int).SetBytes(hash)
+	u1.Neg(u1)
+	u1.Mul(u1, rInv)
+	u1.Mod(u1, secp256k1N)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, secp256k1N)
+
+	curve := Secp256k1()
+	x1, y1 := curve.ScalarBaseMult(u1.Bytes())
+	x2, y2 := curve.ScalarMult(rx, ry, u2.Bytes())
+	qx, qy := curve.Add(x1, y1, x2, y2)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: qx, Y: qy}, nil
+}
+
+// SigToPub recovers the public key from a hash and a 65-byte [R || S || V]
+// signature. V may be 0/1 or 27/28 and high-S signatures are accepted.
+func SigToPub(hash, sig []byte) (*ecdsa.PublicKey, error) {
+	return recoverPublicKey(hash, sig, false)
+}
+
+// Ecrecover returns the 65-byte uncompressed public key that produced sig
+func Ecrecover(hash, sig []byte) ([]byte, error) {
+	pub, err := SigToPub(hash, sig)
+	if err != nil {
+		return nil, err
+	}
+	return secp256k1Marshal(pub.X, pub.Y), nil
+}
+
+// RecoverAddress returns the address of the key that produced sig
+func RecoverAddress(hash, sig []byte) (Address, error) {
+	pub, err := recoverPublicKey(hash, sig, false)
+	if err != nil {
+		return Address{}, err
+	}
+	return PubkeyToAddress(pub), nil
+}
+
+// RecoverAddressStrict is like RecoverAddress but rejects high-S signatures
+// with ErrHighS
+func RecoverAddressStrict(hash, sig []byte) (Address, error) {
+	pub, err := recoverPublicKey(hash, sig, true)
+	if err != nil {
+		return Address{}, err
+	}
+	return PubkeyToAddress(pub), nil
+}
+
+// VerifySignature reports whether sig over hash was produced by the key
+// behind address
+func VerifySignature(address Address, hash, sig []byte) bool {
+	recovered, err := RecoverAddress(hash, sig)
+	return err == nil && recovered == address
+}
+
+// VerifySignatureStrict is like VerifySignature but rejects high-S signatures
+func VerifySignatureStrict(address Address, hash, sig []byte) bool {
+	recovered, err := RecoverAddressStrict(hash, sig)
+	return err == nil && recovered == address
+}
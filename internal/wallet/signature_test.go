@@ -0,0 +1,307 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// Recoverable signatures produced by an independent RFC 6979 implementation
+// over the SHA-256 of "Satoshi Nakamoto", "All those moments will be lost in
+// time, like tears in rain. Time to die...", "" and "abc"
+var signatureVectors = []struct {
+	key       string
+	hash      string
+	signature string
+}{
+	{"0000000000000000000000000000000000000000000000000000000000000001", "a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e", "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e501"},
+	{"0000000000000000000000000000000000000000000000000000000000000001", "7d1833f54854ac51659521afcd0ec6dca2ce2351429614bfa28a756b1b3c637f", "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc2100"},
+	{"0000000000000000000000000000000000000000000000000000000000000001", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "77c8d336572f6f466055b5f70f433851f8f535f6c4fc71133a6cfd71079d03b70ed9f5eb8aa5b266abac35d416c3207e7a538bf5f37649727d7a9823b106957701"},
+	{"0000000000000000000000000000000000000000000000000000000000000001", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", "75601b1385909ea698e3fd6e26e5fa5105127bd2299d3ab0b9d9f93df5b8b99c28ae7cc8f969e6b6fb1feac477818a75a46e8c364e88dfdc9880e1a5175c4bd101"},
+	{"4646464646464646464646464646464646464646464646464646464646464646", "a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e", "e5a7244778a20170e3a9e4f8b1191ce58e56794947161b9fedd4ac2ad28e52ad28d9cc6f1f754e22080f256afd4c878af3c33f76ecab89872a003dd871807bd701"},
+	{"4646464646464646464646464646464646464646464646464646464646464646", "7d1833f54854ac51659521afcd0ec6dca2ce2351429614bfa28a756b1b3c637f", "775c82a1f1f1cb9f789d4641338c5e4b730eda36e541d079b1a6a01257aa9b534ff8a8fd85a89d86b3f78cf1fd093a009819f4eb94c9447e002b89bae41db7b201"},
+	{"4646464646464646464646464646464646464646464646464646464646464646", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "22fe92f734699e03b7cd2f84012f943fdbde6afaae4e8b3f950529602a91dfc82f63da94e8c54502cdf4bb1020fcdd59b73e54ee34bef21c7e529c6255a7eeb901"},
+	{"4646464646464646464646464646464646464646464646464646464646464646", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", "eb09d509499990eca5f2dda1d3b3b01b05eb4efbe2fe366cf8a4c4c0d2b8c96a565a1eef5192f92b470f2e02d763530d238e9db7b0c560b8a9ef35dcaa39f80300"},
+	{"63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9", "a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e", "0e8b2064f4c0966fda30acc65fb5ff80b68127eb9e7a2d6c949a25af26c0fd227231e1c7ee8830a77f410ef183ccbfac551eee89878bb74079b7ed3ac3f2e08701"},
+	{"63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9", "7d1833f54854ac51659521afcd0ec6dca2ce2351429614bfa28a756b1b3c637f", "7a3ba8cc6cb1c03563edea6a8785bf6f90b9a9c681e18c0d97d7bdc44443614128f634518f72792be5ca0efaf66c789358740663079f17e879fd7048215351c800"},
+	{"63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "138e99d2c45af884db48c7ca11e9b1d9d003f3abc47f92ab58586a2b3064ed5013b87bb226aaa4d4671bbf1a1740bb2b0b2e075f671dd14b9522511f74e15a6301"},
+	{"63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", "3db74d08b066b9ff69a77a2f876cce823504b7f2d20b8856c60e7f12043565f3728ca71b0398502b02ca592896d24be17bf0a26f42268757ba0c15b9fc2ab92701"},
+	{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e", "fd567d121db66e382991534ada77a6bd3106f0a1098c231e47993447cd6af2d06b39cd0eb1bc8603e159ef5c20a5c8ad685a45b06ce9bebed3f153d10d93bed500"},
+	{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "7d1833f54854ac51659521afcd0ec6dca2ce2351429614bfa28a756b1b3c637f", "059385ce615b7ab6a0db2a3b83f0566d3bc750e958121635ba497ccb4e3ce801391bf93814fda99c98014ada8567dd7c067a50ac0a7ef7aa613b87e0eec17eb501"},
+	{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "ea045bf0962ecc4d5aa84c8e716c87c9d5f49fba8e1ff0300ab2631de3d83b4351270ec8105346fddf35da5958d99ff55a0c0f720d6ae7f3e3eadd40a9ccfe0e01"},
+	{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", "4a8f571b7915171905f88275618335cea401a8ace744d71789c9361901afd13e53a9847f0e51a8c2ec52c120db27476285baf27b2d97a0fb5b21174f6dc93ec500"},
+}
+
+func TestSignHashVectors(t *testing.T) {
+	for i, tt := range signatureVectors {
+		d, _ := new(big.Int).SetString(tt.key, 16)
+		hash, _ := hex.DecodeString(tt.hash)
+
+		sig, err := signHash(d, hash)
+		if err != nil {
+			t.Errorf("Vector %d: signHash failed: %v", i, err)
+			continue
+		}
+		if got := hex.EncodeToString(sig); got != tt.signature {
+			t.Errorf("Vector %d: signature = %s, want %s", i, got, tt.signature)
+		}
+
+		// The signature verifies against the public key and is low-S
+		x, y := secp256k1ScalarBaseMult(d.Bytes())
+		pub := &ecdsa.PublicKey{Curve: Secp256k1(), X: x, Y: y}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:64])
+		if !ecdsa.Verify(pub, hash, r, s) {
+			t.Errorf("Vector %d: signature does not verify", i)
+		}
+		if s.Cmp(secp256k1HalfN) > 0 {
+			t.Errorf("Vector %d: S is not normalized", i)
+		}
+	}
+}
+
+func TestRFC6979Nonce(t *testing.T) {
+	// Private key 1 signing SHA-256("Satoshi Nakamoto")
+	x := make([]byte, 32)
+	x[31] = 1
+	hash, _ := hex.DecodeString("a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e")
+
+	nonces := rfc6979Nonces(x, hash)
+	want := "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15"
+	if got := hex.EncodeToString(nonces().FillBytes(make([]byte, 32))); got != want {
+		t.Errorf("First nonce = %s, want %s", got, want)
+	}
+
+	// Continuing the generator yields a different valid nonce
+	next := nonces()
+	if next.Sign() <= 0 || next.Cmp(secp256k1N) >= 0 || hex.EncodeToString(next.FillBytes(make([]byte, 32))) == want {
+		t.Errorf("Second nonce %x is invalid", next)
+	}
+}
+
+func TestSignHashEIP155Vector(t *testing.T) {
+	// EIP-155 example transaction signed with key 0x4646...46
+	d, _ := new(big.Int).SetString("4646464646464646464646464646464646464646464646464646464646464646", 16)
+	hash, _ := hex.DecodeString("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53")
+
+	sig, err := signHash(d, hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+
+	want := "28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83" + "00"
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("Signature = %s, want %s", got, want)
+	}
+}
+
+func TestSignHashInvalidInput(t *testing.T) {
+	d := big.NewInt(1)
+	for _, size := range []int{0, 20, 31, 33, 64} {
+		if _, err := signHash(d, make([]byte, size)); !errors.Is(err, ErrInvalidHashLength) {
+			t.Errorf("Hash of %d bytes: expected ErrInvalidHashLength, got %v", size, err)
+		}
+	}
+
+	for _, key := range []*big.Int{big.NewInt(0), new(big.Int).Set(secp256k1N)} {
+		if _, err := signHash(key, make([]byte, HashLength)); err != errInvalidPrivateKey {
+			t.Errorf("Key %x: expected errInvalidPrivateKey, got %v", key, err)
+		}
+	}
+}
+
+func TestWalletSignHash(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	hash, _ := hex.DecodeString("a0dc65ffca799873cbea0ac274015b9526505daaaed385155425f7337704883e")
+	sig, err := wallet.SignHash(account.Address, hash)
+	if err != nil {
+		t.Fatalf("SignHash failed: %v", err)
+	}
+	if len(sig) != SignatureLength {
+		t.Fatalf("Signature length = %d, want %d", len(sig), SignatureLength)
+	}
+
+	// Account 0 is the demo key of the third vector group
+	want := "0e8b2064f4c0966fda30acc65fb5ff80b68127eb9e7a2d6c949a25af26c0fd22" +
+		"7231e1c7ee8830a77f410ef183ccbfac551eee89878bb74079b7ed3ac3f2e087" + "01"
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("Signature = %s, want %s", got, want)
+	}
+
+	// Signing is deterministic
+	again, _ := wallet.SignHash(account.Address, hash)
+	if hex.EncodeToString(again) != hex.EncodeToString(sig) {
+		t.Error("SignHash is not deterministic")
+	}
+
+	if _, err := wallet.SignHash(Address{}, hash); err != ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+	if _, err := wallet.SignHash(account.Address, hash[:20]); !errors.Is(err, ErrInvalidHashLength) {
+		t.Errorf("Expected ErrInvalidHashLength, got %v", err)
+	}
+
+	wallet.isLocked = true
+	if _, err := wallet.SignHash(account.Address, hash); err != ErrWalletLocked {
+		t.Errorf("Expected ErrWalletLocked, got %v", err)
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	for i, tt := range signatureVectors {
+		d, _ := new(big.Int).SetString(tt.key, 16)
+		hash, _ := hex.DecodeString(tt.hash)
+		sig, _ := hex.DecodeString(tt.signature)
+		x, y := secp256k1ScalarBaseMult(d.Bytes())
+		want := PubkeyToAddress(&ecdsa.PublicKey{Curve: Secp256k1(), X: x, Y: y})
+
+		pub, err := SigToPub(hash, sig)
+		if err != nil {
+			t.Errorf("Vector %d: SigToPub failed: %v", i, err)
+			continue
+		}
+		if pub.X.Cmp(x) != 0 || pub.Y.Cmp(y) != 0 {
+			t.Errorf("Vector %d: recovered a different public key", i)
+		}
+
+		uncompressed, err := Ecrecover(hash, sig)
+		if err != nil || hex.EncodeToString(uncompressed) != hex.EncodeToString(secp256k1Marshal(x, y)) {
+			t.Errorf("Vector %d: Ecrecover = %x, %v", i, uncompressed, err)
+		}
+
+		// V may be given as 27/28
+		legacy := append([]byte(nil), sig...)
+		legacy[RecoveryIDOffset] += 27
+		if got, err := RecoverAddressStrict(hash, legacy); err != nil || got != want {
+			t.Errorf("Vector %d: V+27 recovered %s, %v; want %s", i, got.Hex(), err, want.Hex())
+		}
+		if !VerifySignature(want, hash, legacy) || !VerifySignatureStrict(want, hash, sig) {
+			t.Errorf("Vector %d: signature does not verify", i)
+		}
+
+		// The high-S twin recovers the same key unless strict
+		high := append([]byte(nil), sig...)
+		s := new(big.Int).SetBytes(sig[32:64])
+		new(big.Int).Sub(secp256k1N, s).FillBytes(high[32:64])
+		high[RecoveryIDOffset] ^= 1
+		if got, err := RecoverAddress(hash, high); err != nil || got != want {
+			t.Errorf("Vector %d: high-S recovered %s, %v; want %s", i, got.Hex(), err, want.Hex())
+		}
+		if _, err := RecoverAddressStrict(hash, high); err != ErrHighS {
+			t.Errorf("Vector %d: expected ErrHighS, got %v", i, err)
+		}
+		if VerifySignatureStrict(want, hash, high) {
+			t.Errorf("Vector %d: strict verification accepted high S", i)
+		}
+
+		// A different hash recovers a different address
+		other := append([]byte(nil), hash...)
+		other[0] ^= 1
+		if VerifySignature(want, other, sig) {
+			t.Errorf("Vector %d: signature verified against the wrong hash", i)
+		}
+	}
+}
+
+func TestRecoverEIP155Sender(t *testing.T) {
+	hash, _ := hex.DecodeString("daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53")
+	sig, _ := hex.DecodeString("28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83" + "00")
+
+	got, err := RecoverAddress(hash, sig)
+	if err != nil {
+		t.Fatalf("RecoverAddress failed: %v", err)
+	}
+	if want := "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"; got.Hex() != want {
+		t.Errorf("Sender = %s, want %s", got.Hex(), want)
+	}
+}
+
+func TestRecoverInvalidSignature(t *testing.T) {
+	hash, _ := hex.DecodeString(signatureVectors[0].hash)
+	valid, _ := hex.DecodeString(signatureVectors[0].signature)
+
+	withByte := func(i int, b byte) []byte {
+		sig := append([]byte(nil), valid...)
+		sig[i] = b
+		return sig
+	}
+	withR := func(r *big.Int) []byte {
+		sig := append([]byte(nil), valid...)
+		r.FillBytes(sig[:32])
+		return sig
+	}
+	withS := func(s *big.Int) []byte {
+		sig := append([]byte(nil), valid...)
+		s.FillBytes(sig[32:64])
+		return sig
+	}
+
+	tests := []struct {
+		name string
+		hash []byte
+		sig  []byte
+		err  error
+	}{
+		{"short hash", hash[:31], valid, ErrInvalidHashLength},
+		{"short signature", hash, valid[:64], ErrInvalidSignatureLength},
+		{"long signature", hash, append(append([]byte(nil), valid...), 0), ErrInvalidSignatureLength},
+		{"v = 2", hash, withByte(RecoveryIDOffset, 2), ErrInvalidRecoveryID},
+		{"v = 29", hash, withByte(RecoveryIDOffset, 29), ErrInvalidRecoveryID},
+		{"v = 26", hash, withByte(RecoveryIDOffset, 26), ErrInvalidRecoveryID},
+		{"zero r", hash, withR(big.NewInt(0)), ErrInvalidSignature},
+		{"r = n", hash, withR(secp256k1N), ErrInvalidSignature},
+		{"zero s", hash, withS(big.NewInt(0)), ErrInvalidSignature},
+		{"s = n", hash, withS(secp256k1N), ErrInvalidSignature},
+		// No point on the curve has x = 5
+		{"r not on curve", hash, withR(big.NewInt(5)), ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SigToPub(tt.hash, tt.sig); !errors.Is(err, tt.err) {
+				t.Errorf("SigToPub error = %v, want %v", err, tt.err)
+			}
+			if VerifySignature(Address{}, tt.hash, tt.sig) {
+				t.Error("VerifySignature accepted an invalid signature")
+			}
+		})
+	}
+}
+
+func TestWalletSignAndRecover(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	for i := uint32(0); i < 3; i++ {
+		account, err := wallet.Derive(i)
+		if err != nil {
+			t.Fatalf("Failed to derive account: %v", err)
+		}
+
+		hash := keccak256([]byte{byte(i)})
+		sig, err := wallet.SignHash(account.Address, hash)
+		if err != nil {
+			t.Fatalf("SignHash failed: %v", err)
+		}
+		if !VerifySignatureStrict(account.Address, hash, sig) {
+			t.Errorf("Account %d: signature does not verify", i)
+		}
+	}
+}
@@ -9,20 +9,24 @@
 //
 // Standards Compliance:
 // - BIP-39: Mnemonic code for generating deterministic keys
+// - BIP-32: Hierarchical deterministic key derivation
+// - SEC 2: secp256k1 elliptic curve
 package wallet
 
 import (
 	"crypto/ecdsa"
-	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -36,6 +40,8 @@ const (
 	MaxEntropyBits = 256
 	// AddressLength represents the byte length of an Ethereum address
 	AddressLength = 20
+	// SeedIterations is the PBKDF2 iteration count for BIP-39 seed derivation
+	SeedIterations = 2048
 )
 
 // Error definitions
@@ -44,10 +50,14 @@ var (
 	ErrInvalidPath         = errors.New("invalid derivation path")
 	ErrAccountNotFound     = errors.New("account not found")
 	ErrInvalidEntropy      = errors.New("invalid entropy bits")
+	ErrInvalidEntropyLen   = errors.New("invalid entropy length")
 	ErrWalletLocked        = errors.New("wallet is locked")
 	ErrInvalidPassphrase   = errors.New("invalid passphrase")
 	ErrInvalidSeed         = errors.New("invalid seed length")
 	ErrKeyDerivationFailed = errors.New("key derivation failed")
+	ErrInvalidWordCount    = errors.New("invalid mnemonic word count")
+	ErrUnknownWord         = errors.New("unknown mnemonic word")
+	ErrInvalidChecksum     = errors.New("invalid mnemonic checksum")
 )
 
 // Address represents an Ethereum address
@@ -56,11 +66,17 @@ type Address [AddressLength]byte
 // DerivationPath represents a BIP-32 derivation path
 type DerivationPath []uint32
 
-// Account represents a wallet account
+// Account represents a wallet account. The *Account values returned by the
+// wallet are shared with it: Lock, auto-lock and Close clear PrivateKey (and
+// zero the key it pointed to) at any time, and SetAccountLabel changes Label,
+// without synchronizing with the caller. Use the private key only through wallet methods such as SignHash
+// and GetPrivateKeyHex, which hold the wallet's lock.
 type Account struct {
-	Address    Address
-	Path       string
-	Index      uint32
+	Address Address
+	Path    string
+	Index   uint32
+	Label   string
+	// PrivateKey is nil while the wallet is locked; see the note above
 	PrivateKey *ecdsa.PrivateKey
 	PublicKey  *ecdsa.PublicKey
 	CreatedAt  time.Time
@@ -71,20 +87,55 @@ type SimpleWallet struct {
 	// Core wallet data
 	mnemonic  string
 	seed      []byte
-	masterKey *ecdsa.PrivateKey
+	masterKey *ExtendedKey
+	scheme    DerivationScheme
 
 	// Account management
 	accounts map[Address]*Account
 	paths    map[Address]DerivationPath
 
-	// Security and state management
+	// Vault identity, set by Save and OpenWallet
+	id    string
+	label string
+
+	// Security and state management. sealed is the copy of the seed and
+	// mnemonic that Unlock restores; it is nil without a lock passphrase.
 	isLocked bool
+	isClosed bool
+	sealed   *sealedSecrets
 	mu       sync.RWMutex
+
+	// Auto-lock state. lastUsed holds UnixNano so that read-locked signing
+	// can record a use.
+	autoLockIdle time.Duration
+	autoLockMax  time.Duration
+	onLockEvent  func(LockEvent)
+	lastUsed     atomic.Int64
+	unlockedAt   time.Time
+	lockTimer    *time.Timer
 }
 
 // WalletConfig holds configuration options for wallet creation
 type WalletConfig struct {
+	// Passphrase is the optional BIP-39 passphrase of NewFromMnemonic
 	Passphrase string
+	// LockPassphrase enables Lock: the seed and mnemonic are sealed under it
+	// when the wallet is created, and Unlock takes it back. The wallet does
+	// not keep it. Empty disables locking and auto-lock.
+	LockPassphrase string
+	// Scheme names the derivation scheme used by Derive; empty selects
+	// SchemeBIP44
+	Scheme string
+	// AutoLockIdle locks the wallet once this long has passed without a
+	// derivation, private key export or signature; zero disables it. Auto-lock
+	// requires LockPassphrase.
+	AutoLockIdle time.Duration
+	// AutoLockMax locks the wallet this long after it was created or last
+	// unlocked, however often it is used; zero disables it
+	AutoLockMax time.Duration
+	// OnLockEvent, if set, is called after the wallet locks or unlocks. It
+	// runs on the auto-lock timer's goroutine for automatic locks.
+	OnLockEvent func(LockEvent)
 }
 
 // DefaultConfig returns a default wallet configuration
@@ -103,41 +154,105 @@ func init() {
 	}
 }
 
-// validateMnemonic performs comprehensive BIP-39 validation of a mnemonic phrase
-func validateMnemonic(mnemonic string) bool {
+// validateMnemonic performs comprehensive BIP-39 validation of a mnemonic phrase.
+// It returns ErrInvalidWordCount, ErrUnknownWord or ErrInvalidChecksum
+// describing the first problem found, or nil if the phrase is valid.
+func validateMnemonic(mnemonic string) error {
+	entropy, err := mnemonicToEntropy(mnemonic)
+	if err != nil {
+		return err
+	}
+	secureClear(entropy)
+	return nil
+}
+
+// entropyToMnemonic encodes entropy as a BIP-39 mnemonic phrase. The entropy is
+// followed by the first len(entropy)*8/32 bits of its SHA-256 hash and the
+// resulting bit string is split into 11-bit indexes into BIP39WordList.
+func entropyToMnemonic(entropy []byte) string {
+	entropyBits := len(entropy) * 8
+	checksumBits := entropyBits / 32
+	wordCount := (entropyBits + checksumBits) / 11
+
+	// The checksum never exceeds 8 bits, so one hash byte is enough
+	checksum := sha256.Sum256(entropy)
+	data := make([]byte, len(entropy)+1)
+	copy(data, entropy)
+	data[len(entropy)] = checksum[0]
+	defer secureClear(data)
+
+	words := make([]string, wordCount)
+	for i := 0; i < wordCount; i++ {
+		index := 0
+		for j := 0; j < 11; j++ {
+			bit := i*11 + j
+			index <<= 1
+			if data[bit/8]&(0x80>>(bit%8)) != 0 {
+				index |= 1
+			}
+		}
+		words[i] = BIP39WordList[index]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// mnemonicToEntropy decodes a BIP-39 mnemonic phrase back into its entropy,
+// verifying the word count, word list membership and checksum
+func mnemonicToEntropy(mnemonic string) ([]byte, error) {
 	words := strings.Fields(mnemonic)
 
 	// Validate word count (BIP-39 standard: 12, 15, 18, 21, or 24 words)
 	wordCount := len(words)
 	if wordCount != 12 && wordCount != 15 && wordCount != 18 && wordCount != 21 && wordCount != 24 {
-		return false
+		return nil, fmt.Errorf("%w: %d", ErrInvalidWordCount, wordCount)
 	}
 
-	// Validate each word exists in the BIP-39 word list
-	for _, word := range words {
-		if _, exists := bip39WordMap[word]; !exists {
-			return false
+	totalBits := wordCount * 11
+	checksumBits := totalBits / 33
+	entropyBytes := (totalBits - checksumBits) / 8
+
+	// Unpack the 11-bit word indexes into entropy followed by checksum bits
+	data := make([]byte, entropyBytes+1)
+	for i, word := range words {
+		index, exists := bip39WordMap[word]
+		if !exists {
+			secureClear(data)
+			return nil, fmt.Errorf("%w: word %d %q", ErrUnknownWord, i+1, word)
+		}
+		for j := 0; j < 11; j++ {
+			if index&(1<<(10-j)) != 0 {
+				bit := i*11 + j
+				data[bit/8] |= 0x80 >> (bit % 8)
+			}
 		}
 	}
 
-	return true
-}
+	entropy := make([]byte, entropyBytes)
+	copy(entropy, data[:entropyBytes])
+	checksum := data[entropyBytes]
+	secureClear(data)
 
-// generateSeedFromMnemonic creates a seed from a mnemonic phrase
-func generateSeedFromMnemonic(mnemonic, passphrase string) []byte {
-	// Simple seed generation using SHA-256 hash
-	combined := mnemonic + passphrase
-	hash := sha256.Sum256([]byte(combined))
+	expected := sha256.Sum256(entropy)
+	mask := byte(0xFF << (8 - checksumBits))
+	if checksum&mask != expected[0]&mask {
+		secureClear(entropy)
+		return nil, ErrInvalidChecksum
+	}
 
-	// Extend to 64 bytes
-	seed := make([]byte, SeedLength)
-	copy(seed, hash[:])
+	return entropy, nil
+}
 
-	// Second hash for remaining bytes
-	hash2 := sha256.Sum256(hash[:])
-	copy(seed[32:], hash2[:])
+// generateSeedFromMnemonic derives the BIP-39 seed from a mnemonic phrase using
+// PBKDF2-HMAC-SHA512 with the NFKD-normalized mnemonic as password and
+// "mnemonic" followed by the NFKD-normalized passphrase as salt
+func generateSeedFromMnemonic(mnemonic, passphrase string) []byte {
+	password := []byte(normalizeNFKD(mnemonic))
+	salt := []byte("mnemonic" + normalizeNFKD(passphrase))
+	defer secureClear(password)
+	defer secureClear(salt)
 
-	return seed
+	return pbkdf2Key(password, salt, SeedIterations, SeedLength, sha512.New)
 }
 
 // NewFromMnemonic creates a new wallet from a BIP-39 mnemonic phrase
@@ -147,8 +262,8 @@ func NewFromMnemonic(mnemonic string, config *WalletConfig) (*SimpleWallet, erro
 	}
 
 	// Validate mnemonic
-	if !validateMnemonic(mnemonic) {
-		return nil, ErrInvalidMnemonic
+	if err := validateMnemonic(mnemonic); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMnemonic, err)
 	}
 
 	// Generate seed from mnemonic
@@ -175,23 +290,47 @@ func NewFromSeed(seed []byte, config *WalletConfig) (*SimpleWallet, error) {
 
 // newWallet creates a new wallet instance with proper initialization
 func newWallet(mnemonic string, seed []byte, config *WalletConfig) (*SimpleWallet, error) {
-	// Create a master key using the seed
-	masterKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	scheme, err := LookupDerivationScheme(config.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.LockPassphrase == "" && (config.AutoLockIdle > 0 || config.AutoLockMax > 0) {
+		return nil, fmt.Errorf("%w: auto-lock requires one", ErrNoLockPassphrase)
+	}
+
+	// Create the BIP-32 master node from the seed
+	masterKey, err := NewMasterKey(seed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create master key: %w", err)
 	}
 
+	var sealed *sealedSecrets
+	if config.LockPassphrase != "" {
+		if sealed, err = sealSecrets(config.LockPassphrase, seed, mnemonic); err != nil {
+			masterKey.Zero()
+			return nil, err
+		}
+	}
+
 	wallet := &SimpleWallet{
 		mnemonic:  mnemonic,
 		seed:      make([]byte, len(seed)),
 		masterKey: masterKey,
+		scheme:    scheme,
 		accounts:  make(map[Address]*Account),
 		paths:     make(map[Address]DerivationPath),
 		isLocked:  false,
+		sealed:    sealed,
+
+		autoLockIdle: config.AutoLockIdle,
+		autoLockMax:  config.AutoLockMax,
+		onLockEvent:  config.OnLockEvent,
 	}
 
 	// Secure copy of seed
 	copy(wallet.seed, seed)
+	wallet.startAutoLock()
 
 	// Set up finalizer for secure cleanup
 	runtime.SetFinalizer(wallet, (*SimpleWallet).cleanup)
@@ -205,56 +344,66 @@ func GenerateMnemonic(entropyBits int) (string, error) {
 		return "", ErrInvalidEntropy
 	}
 
-	// BIP-39 standard word count based on entropy
-	var wordCount int
-	switch entropyBits {
-	case 128:
-		wordCount = 12
-	case 160:
-		wordCount = 15
-	case 192:
-		wordCount = 18
-	case 224:
-		wordCount = 21
-	case 256:
-		wordCount = 24
-	default:
-		return "", ErrInvalidEntropy
+	// Draw the entropy; the checksum and word mapping follow BIP-39
+	entropy := make([]byte, entropyBits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
+	defer secureClear(entropy)
 
-	words := make([]string, wordCount)
+	return entropyToMnemonic(entropy), nil
+}
 
-	// Generate each word using proper cryptographic randomness
-	// BIP39 has 2048 words, so we need 11 bits per word (2^11 = 2048)
-	for i := 0; i < wordCount; i++ {
-		// Use crypto/rand for each word selection to ensure uniform distribution
-		var wordIndex int
-		for {
-			// Generate enough random bytes for uniform distribution
-			randomBytes := make([]byte, 2) // 16 bits to avoid modulus bias
-			_, err := rand.Read(randomBytes)
-			if err != nil {
-				return "", fmt.Errorf("failed to generate random bytes: %w", err)
-			}
+// MnemonicFromEntropy converts raw entropy into a checksummed BIP-39 mnemonic.
+// The entropy must be 16 to 32 bytes long in multiples of 4 bytes; other
+// lengths return ErrInvalidEntropyLen.
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < MinEntropyBits || bits > MaxEntropyBits || bits%32 != 0 {
+		return "", fmt.Errorf("%w: %d bytes", ErrInvalidEntropyLen, len(entropy))
+	}
 
-			// Convert to uint16 and check if it's in the uniform range
-			randomValue := uint16(randomBytes[0])<<8 | uint16(randomBytes[1])
+	return entropyToMnemonic(entropy), nil
+}
 
-			// To avoid modulus bias, only accept values in range [0, 2048*floor(65536/2048))
-			// floor(65536/2048) = 32, so range is [0, 65536)
-			// Since 65536 is evenly divisible by 2048, we can use any value
-			wordIndex = int(randomValue % 2048)
-			break
-		}
+// EntropyFromMnemonic recovers the raw entropy encoded by a BIP-39 mnemonic.
+// It returns ErrInvalidWordCount, ErrUnknownWord or ErrInvalidChecksum if the
+// phrase is not a valid mnemonic. Callers should clear the returned slice once
+// it is no longer needed.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	return mnemonicToEntropy(mnemonic)
+}
 
-		words[i] = BIP39WordList[wordIndex]
+// Derive derives a new account at the specified index using the wallet's
+// derivation scheme, m/44'/60'/0'/0/index by default. It returns
+// ErrInvalidPath if index is not below HardenedKeyStart, as the scheme would
+// otherwise derive a different account from the one asked for.
+func (w *SimpleWallet) Derive(index uint32) (*Account, error) {
+	path, err := w.scheme.Path(index)
+	if err != nil {
+		return nil, err
 	}
+	return w.deriveAccount(path, index)
+}
 
-	return strings.Join(words, " "), nil
+// Scheme returns the derivation scheme used by Derive
+func (w *SimpleWallet) Scheme() DerivationScheme {
+	return w.scheme
 }
 
-// Derive derives a new account at the specified index
-func (w *SimpleWallet) Derive(index uint32) (*Account, error) {
+// DeriveAtPath derives a new account at an arbitrary BIP-32 path. The
+// account's Index is the last path component without the hardened flag.
+func (w *SimpleWallet) DeriveAtPath(path DerivationPath) (*Account, error) {
+	var index uint32
+	if len(path) > 0 {
+		index = path[len(path)-1] &^ HardenedKeyStart
+	}
+	return w.deriveAccount(path, index)
+}
+
+// deriveAccount derives and records the account at path under the given
+// account index
+func (w *SimpleWallet) deriveAccount(path DerivationPath, index uint32) (*Account, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -262,20 +411,15 @@ func (w *SimpleWallet) Derive(index uint32) (*Account, error) {
 		return nil, ErrWalletLocked
 	}
 
-	// Simple derivation path: m/44'/60'/0'/0/index
-	path := DerivationPath{
-		0x8000002C, // Purpose: 44'
-		0x8000003C, // Coin type: 60' (Ethereum)
-		0x80000000, // Account: 0'
-		0,          // Change: 0
-		index,      // Address index
-	}
+	// Keep a private copy so callers cannot mutate the recorded path
+	path = append(DerivationPath(nil), path...)
 
 	// Derive the private key
 	privateKey, err := w.derivePrivateKey(path)
 	if err != nil {
 		return nil, fmt.Errorf("key derivation failed: %w", err)
 	}
+	w.touch()
 
 	// Get the public key
 	publicKey := &privateKey.PublicKey
@@ -292,6 +436,10 @@ func (w *SimpleWallet) Derive(index uint32) (*Account, error) {
 		PublicKey:  publicKey,
 		CreatedAt:  time.Now(),
 	}
+	if existing, exists := w.accounts[address]; exists {
+		account.Label = existing.Label
+		account.CreatedAt = existing.CreatedAt
+	}
 
 	// Store account
 	w.accounts[address] = account
@@ -300,38 +448,72 @@ func (w *SimpleWallet) Derive(index uint32) (*Account, error) {
 	return account, nil
 }
 
-// derivePrivateKey derives a private key at the specified path using a simple derivation
+// DeriveAtPathString parses path with ParseDerivationPath and derives the
+// account at it
+func (w *SimpleWallet) DeriveAtPathString(path string) (*Account, error) {
+	parsed, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return w.DeriveAtPath(parsed)
+}
+
+// Path returns the derivation path recorded for a derived account
+func (w *SimpleWallet) Path(address Address) (DerivationPath, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	path, exists := w.paths[address]
+	if !exists {
+		return nil, ErrAccountNotFound
+	}
+	return append(DerivationPath(nil), path...), nil
+}
+
+// derivePrivateKey derives the BIP-32 private key at the specified path from the master node
 func (w *SimpleWallet) derivePrivateKey(path DerivationPath) (*ecdsa.PrivateKey, error) {
-	// Simple key derivation using seed and path components
-	hash := sha256.New()
-	hash.Write(w.seed)
+	if w.masterKey == nil {
+		return nil, ErrKeyDerivationFailed
+	}
 
-	// Add path components to the hash
-	for _, component := range path {
-		pathBytes := make([]byte, 4)
-		pathBytes[0] = byte(component >> 24)
-		pathBytes[1] = byte(component >> 16)
-		pathBytes[2] = byte(component >> 8)
-		pathBytes[3] = byte(component)
-		hash.Write(pathBytes)
+	child, err := w.masterKey.DerivePath(path)
+	if err != nil {
+		return nil, err
 	}
+	defer child.Zero()
 
-	keyBytes := hash.Sum(nil)
+	keyBytes, err := child.PrivateKeyBytes()
+	if err != nil {
+		return nil, err
+	}
+	defer secureClear(keyBytes)
 
-	// Create private key from hash
+	// The BIP-32 node already carries the public key; decompress it rather
+	// than repeating the scalar multiplication
+	x, y, err := secp256k1Decompress(child.PublicKeyBytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyDerivationFailed, err)
+	}
+
+	// Create private key from the derived key material
 	privateKey := new(ecdsa.PrivateKey)
-	privateKey.PublicKey.Curve = elliptic.P256()
+	privateKey.PublicKey.Curve = Secp256k1()
 	privateKey.D = new(big.Int).SetBytes(keyBytes)
-	privateKey.PublicKey.X, privateKey.PublicKey.Y = privateKey.PublicKey.Curve.ScalarBaseMult(keyBytes)
+	privateKey.PublicKey.X, privateKey.PublicKey.Y = x, y
 
 	return privateKey, nil
 }
 
 // pubkeyToAddress converts a public key to an Ethereum address
 func (w *SimpleWallet) pubkeyToAddress(pubkey *ecdsa.PublicKey) Address {
-	// Simple address derivation using hash of public key
-	pubkeyBytes := elliptic.Marshal(pubkey.Curve, pubkey.X, pubkey.Y)
-	hash := sha256.Sum256(pubkeyBytes[1:]) // Skip the 0x04 prefix
+	return PubkeyToAddress(pubkey)
+}
+
+// PubkeyToAddress converts a secp256k1 public key to an Ethereum address
+func PubkeyToAddress(pubkey *ecdsa.PublicKey) Address {
+	// The address is the last 20 bytes of the Keccak-256 hash of the public key
+	pubkeyBytes := secp256k1Marshal(pubkey.X, pubkey.Y)
+	hash := keccak256(pubkeyBytes[1:]) // Skip the 0x04 prefix
 
 	var addr Address
 	copy(addr[:], hash[12:]) // Take last 20 bytes
@@ -351,8 +533,10 @@ func (w *SimpleWallet) GetPrivateKeyHex(address Address) (string, error) {
 	if w.isLocked {
 		return "", ErrWalletLocked
 	}
+	w.touch()
 
-	privateKeyBytes := account.PrivateKey.D.Bytes()
+	privateKeyBytes := account.PrivateKey.D.FillBytes(make([]byte, 32))
+	defer secureClear(privateKeyBytes)
 	return hex.EncodeToString(privateKeyBytes), nil
 }
 
@@ -366,7 +550,7 @@ func (w *SimpleWallet) GetPublicKeyHex(address Address) (string, error) {
 		return "", ErrAccountNotFound
 	}
 
-	publicKeyBytes := elliptic.Marshal(account.PublicKey.Curve, account.PublicKey.X, account.PublicKey.Y)
+	publicKeyBytes := secp256k1Marshal(account.PublicKey.X, account.PublicKey.Y)
 	return hex.EncodeToString(publicKeyBytes[1:]), nil // Remove 0x04 prefix
 }
 
@@ -412,16 +596,35 @@ func (w *SimpleWallet) Close() error {
 	return nil
 }
 
+// closed reports whether Close has been called
+func (w *SimpleWallet) closed() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.isClosed
+}
+
 // cleanup performs secure cleanup of sensitive data
 func (w *SimpleWallet) cleanup() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	w.isClosed = true
+
 	// Clear sensitive data
 	if w.seed != nil {
 		secureClear(w.seed)
 		w.seed = nil
 	}
+	if w.masterKey != nil {
+		w.masterKey.Zero()
+		w.masterKey = nil
+	}
+	w.sealed = nil
+	if w.lockTimer != nil {
+		w.lockTimer.Stop()
+		w.lockTimer = nil
+	}
 
 	// Clear private keys from accounts
 	for _, account := range w.accounts {
@@ -468,9 +671,9 @@ func formatDerivationPath(path DerivationPath) string {
 	return result
 }
 
-// Hex returns the hex representation of the address
+// Hex returns the EIP-55 mixed-case checksum encoding of the address
 func (a Address) Hex() string {
-	return "0x" + hex.EncodeToString(a[:])
+	return a.checksumHex()
 }
 
 // String returns the string representation of the address
@@ -483,14 +686,74 @@ func (a Address) Bytes() []byte {
 	return a[:]
 }
 
-// ParseDerivationPath parses a derivation path string
+// ParseDerivationPath parses a BIP-32 derivation path string.
+//
+// Absolute paths start with "m" (for example m/44'/60'/0'/0/0); relative paths
+// such as "0/7" are appended to DefaultRootDerivationPath. Hardened components
+// may be marked with ', h or H. Component values must be below 2^31. An empty
+// string or "m" denotes the master node.
 func ParseDerivationPath(path string) (DerivationPath, error) {
-	// Simple parser - in production, use a proper BIP-32 parser
+	path = strings.TrimSpace(path)
 	if path == "m" || path == "" {
 		return DerivationPath{}, nil
 	}
 
-	return DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, 0}, nil
+	var result DerivationPath
+	segments := strings.Split(path, "/")
+	switch {
+	case segments[0] == "m":
+		segments = segments[1:]
+	case strings.HasPrefix(segments[0], "m"):
+		return nil, fmt.Errorf("%w: segment 1 %q: expected \"m\"", ErrInvalidPath, segments[0])
+	default:
+		result = append(result, DefaultRootDerivationPath...)
+	}
+
+	for i, segment := range segments {
+		component, err := parsePathComponent(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%w: segment %d %q: %v", ErrInvalidPath, i+1, segment, err)
+		}
+		result = append(result, component)
+	}
+
+	return result, nil
+}
+
+// parsePathComponent parses a single path component such as 44' or 0
+func parsePathComponent(segment string) (uint32, error) {
+	if segment == "" {
+		return 0, errors.New("empty component")
+	}
+
+	var offset uint32
+	switch segment[len(segment)-1] {
+	case '\'', 'h', 'H':
+		offset = HardenedKeyStart
+		segment = segment[:len(segment)-1]
+	}
+
+	// Only plain decimal digits are accepted; signs and spaces are rejected
+	if segment == "" {
+		return 0, errors.New("missing index")
+	}
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid character %q", r)
+		}
+	}
+
+	value, err := strconv.ParseUint(segment, 10, 32)
+	if err != nil || value >= HardenedKeyStart {
+		return 0, fmt.Errorf("index out of range (must be below %d)", uint32(HardenedKeyStart))
+	}
+
+	return uint32(value) + offset, nil
+}
+
+// String returns the path in m/44'/60'/0'/0/0 notation
+func (p DerivationPath) String() string {
+	return formatDerivationPath(p)
 }
 
 // StrictParseDerivationPath parses a derivation path and panics on error
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"encoding/hex"
+	"errors"
 	"regexp"
 	"strings"
 	"testing"
@@ -9,11 +11,12 @@ import (
 // Test constants
 const (
 	testMnemonic12  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
-	testMnemonic15  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
-	testMnemonic18  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
-	testMnemonic21  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
-	testMnemonic24  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testMnemonic15  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon address"
+	testMnemonic18  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent"
+	testMnemonic21  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon admit"
+	testMnemonic24  = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art"
 	invalidMnemonic = "invalid word list test validation check system"
+	demoMnemonic    = "tag volcano eight thank tide danger coast health above argue embrace heavy"
 )
 
 func TestBIP39WordListInitialization(t *testing.T) {
@@ -50,31 +53,273 @@ func TestValidateMnemonic(t *testing.T) {
 	tests := []struct {
 		name     string
 		mnemonic string
-		valid    bool
+		err      error
 	}{
-		{"Valid 12-word mnemonic", testMnemonic12, true},
-		{"Valid 15-word mnemonic", testMnemonic15, true},
-		{"Valid 18-word mnemonic", testMnemonic18, true},
-		{"Valid 21-word mnemonic", testMnemonic21, true},
-		{"Valid 24-word mnemonic", testMnemonic24, true},
-		{"Invalid word count (11 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", false},
-		{"Invalid word count (13 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", false},
-		{"Invalid words", invalidMnemonic, false},
-		{"Empty mnemonic", "", false},
-		{"Single word", "abandon", false},
-		{"Mixed valid/invalid words", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon invalid", false},
+		{"Valid 12-word mnemonic", testMnemonic12, nil},
+		{"Valid 15-word mnemonic", testMnemonic15, nil},
+		{"Valid 18-word mnemonic", testMnemonic18, nil},
+		{"Valid 21-word mnemonic", testMnemonic21, nil},
+		{"Valid 24-word mnemonic", testMnemonic24, nil},
+		{"Invalid word count (11 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidWordCount},
+		{"Invalid word count (13 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidWordCount},
+		{"Invalid words", invalidMnemonic, ErrInvalidWordCount},
+		{"Empty mnemonic", "", ErrInvalidWordCount},
+		{"Single word", "abandon", ErrInvalidWordCount},
+		{"Mixed valid/invalid words", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon invalid", ErrUnknownWord},
+		{"Bad checksum (12 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidChecksum},
+		{"Bad checksum (24 words)", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", ErrInvalidChecksum},
+		{"Bad checksum (last word)", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo", ErrInvalidChecksum},
+		{"Not a BIP-39 word", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo why", ErrUnknownWord},
+		{"Punctuated word", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo, wrong", ErrUnknownWord},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := validateMnemonic(tt.mnemonic)
-			if result != tt.valid {
-				t.Errorf("validateMnemonic(%q) = %v, want %v", tt.mnemonic, result, tt.valid)
+			err := validateMnemonic(tt.mnemonic)
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("validateMnemonic(%q) = %v, want nil", tt.mnemonic, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("validateMnemonic(%q) = %v, want %v", tt.mnemonic, err, tt.err)
 			}
 		})
 	}
 }
 
+// bip39Vector is an entry from the official BIP-39 English test vectors
+// published at https://github.com/trezor/python-mnemonic/blob/master/vectors.json
+// All seeds are derived with the passphrase "TREZOR".
+type bip39Vector struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}
+
+var bip39Vectors = []bip39Vector{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		"000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon agent",
+		"035895f2f481b1b0f01fcf8c289c794660b289981a78f8106447707fdd9666ca06da5a9a565181599b79f53b844d8a71dd9f439c52a3d7b3e8a79c906ac845fa",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal will",
+		"f2b94508732bcbacbcc020faefecfc89feafa6649a5491b8c952cede496c214a0c7b3c392d168748f2d4a612bada0753b52a1c7ac53c1e93abd5c6320b9e95dd",
+	},
+	{
+		"808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+		"0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth title",
+		"bc09fca1804f7e69da93c2f2028eb238c227f2e9dda30cd63699232578480a4021b146ad717fbb7e451ce9eb835f43620bf5c514db0f8add49f5d121449d3e87",
+	},
+	{
+		"8080808080808080808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic bless",
+		"c0c519bd0e91a2ed54357d9d1ebef6f5af218a153624cf4f2da911a0ed8f7a09e2ef61af0aca007096df430022f7a2b6fb91661a9589097069720d015e4e982f",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+	{
+		"77c2b00716cec7213839159e404db50d",
+		"jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		"b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+	},
+	{
+		"b63a9c59a6e641f288ebc103017f1da9f8290b3da6bdef7b",
+		"renew stay biology evidence goat welcome casual join adapt armor shuffle fault little machine walk stumble urge swap",
+		"9248d83e06f4cd98debf5b6f010542760df925ce46cf38a1bdb4e4de7d21f5c39366941c69e1bdbf2966e0f6e6dbece898a0e2f0a4c2b3e640953dfe8b7bbdc5",
+	},
+	{
+		"3e141609b97933b66a060dcddc71fad1d91677db872031e85f4c015c5e7e8982",
+		"dignity pass list indicate nasty swamp pool script soccer toe leaf photo multiply desk host tomato cradle drill spread actor shine dismiss champion exotic",
+		"ff7f3184df8696d8bef94b6c03114dbee0ef89ff938712301d27ed8336ca89ef9635da20af07d4175f2bf5f3de130f39c9d9e8dd0472489c19b1a020a940da67",
+	},
+	{
+		"0460ef47585604c5660618db2e6a7e7f",
+		"afford alter spike radar gate glance object seek swamp infant panel yellow",
+		"65f93a9f36b6c85cbe634ffc1f99f2b82cbb10b31edc7f087b4f6cb9e976e9faf76ff41f8f27c99afdf38f7a303ba1136ee48a4c1e7fcd3dba7aa876113a36e4",
+	},
+	{
+		"72f60ebac5dd8add8d2a25a797102c3ce21bc029c200076f",
+		"indicate race push merry suffer human cruise dwarf pole review arch keep canvas theme poem divorce alter left",
+		"3bbf9daa0dfad8229786ace5ddb4e00fa98a044ae4c4975ffd5e094dba9e0bb289349dbe2091761f30f382d4e35c4a670ee8ab50758d2c55881be69e327117ba",
+	},
+	{
+		"2c85efc7f24ee4573d2b81a6ec66cee209b2dcbd09d8eddc51e0215b0b68e416",
+		"clutch control vehicle tonight unusual clog visa ice plunge glimpse recipe series open hour vintage deposit universe tip job dress radar refuse motion taste",
+		"fe908f96f46668b2d5b37d82f558c77ed0d69dd0e7e043a5b0511c48c2f1064694a956f86360c93dd04052a8899497ce9e985ebe0c8c52b955e6ae86d4ff4449",
+	},
+	{
+		"eaebabb2383351fd31d703840b32e9e2",
+		"turtle front uncle idea crush write shrug there lottery flower risk shell",
+		"bdfb76a0759f301b0b899a1e3985227e53b3f51e67e3f2a65363caedf3e32fde42a66c404f18d7b05818c95ef3ca1e5146646856c461c073169467511680876c",
+	},
+	{
+		"7ac45cfe7722ee6c7ba84fbc2d5bd61b45cb2fe5eb65aa78",
+		"kiss carry display unusual confirm curtain upgrade antique rotate hello void custom frequent obey nut hole price segment",
+		"ed56ff6c833c07982eb7119a8f48fd363c4a9b1601cd2de736b01045c5eb8ab4f57b079403485d1c4924f0790dc10a971763337cb9f9c62226f64fff26397c79",
+	},
+	{
+		"4fa1a8bc3e6d80ee1316050e862c1812031493212b7ec3f3bb1b08f168cabeef",
+		"exile ask congress lamp submit jacket era scheme attend cousin alcohol catch course end lucky hurt sentence oven short ball bird grab wing top",
+		"095ee6f817b4c2cb30a5a797360a81a40ab0f9a4e25ecd672a3f58a0b5ba0687c096a6b14d2c0deb3bdefce4f61d01ae07417d502429352e27695163f7447a8c",
+	},
+	{
+		"18ab19a9f54a9274f03e5209a2ac8a91",
+		"board flee heavy tunnel powder denial science ski answer betray cargo cat",
+		"6eff1bb21562918509c73cb990260db07c0ce34ff0e3cc4a8cb3276129fbcb300bddfe005831350efd633909f476c45c88253276d9fd0df6ef48609e8bb7dca8",
+	},
+	{
+		"18a2e1d81b8ecfb2a333adcb0c17a5b9eb76cc5d05db91a4",
+		"board blade invite damage undo sun mimic interest slam gaze truly inherit resist great inject rocket museum chief",
+		"f84521c777a13b61564234bf8f8b62b3afce27fc4062b51bb5e62bdfecb23864ee6ecf07c1d5a97c0834307c5c852d8ceb88e7c97923c0a3b496bedd4e5f88a9",
+	},
+	{
+		"15da872c95a13dd738fbf50e427583ad61f18fd99f628c417a61cf8343c90419",
+		"beyond stage sleep clip because twist token leaf atom beauty genius food business side grid unable middle armed observe pair crouch tonight away coconut",
+		"b15509eaa2d09d3efd3e006ef42151b30367dc6e3aa5e44caba3fe4d3e352e65101fbdb86a96776b91946ff06f8eac594dc6ee1d3e82a42dfe1b40fef6bcc3fd",
+	},
+}
+
+func TestBIP39Vectors(t *testing.T) {
+	for _, v := range bip39Vectors {
+		entropy, err := hex.DecodeString(v.entropy)
+		if err != nil {
+			t.Fatalf("bad test entropy %q: %v", v.entropy, err)
+		}
+
+		if mnemonic := entropyToMnemonic(entropy); mnemonic != v.mnemonic {
+			t.Errorf("entropyToMnemonic(%s) = %q, want %q", v.entropy, mnemonic, v.mnemonic)
+		}
+
+		if err := validateMnemonic(v.mnemonic); err != nil {
+			t.Errorf("validateMnemonic(%q) = %v, want nil", v.mnemonic, err)
+		}
+
+		decoded, err := mnemonicToEntropy(v.mnemonic)
+		if err != nil {
+			t.Errorf("mnemonicToEntropy(%q) failed: %v", v.mnemonic, err)
+			continue
+		}
+		if hex.EncodeToString(decoded) != v.entropy {
+			t.Errorf("mnemonicToEntropy(%q) = %x, want %s", v.mnemonic, decoded, v.entropy)
+		}
+	}
+}
+
+func TestGenerateSeedFromMnemonic(t *testing.T) {
+	for _, v := range bip39Vectors {
+		seed := generateSeedFromMnemonic(v.mnemonic, "TREZOR")
+		if got := hex.EncodeToString(seed); got != v.seed {
+			t.Errorf("generateSeedFromMnemonic(%q) = %s, want %s", v.mnemonic, got, v.seed)
+		}
+	}
+
+	// Passphrases are NFKD-normalized, so composed and decomposed forms match
+	tests := []struct {
+		passphrase string
+		seed       string
+	}{
+		{"Crème brûlée ① ﬁ", "a5fd3b1b6d49cb96ec74a454daa5d2ec6654975a632755b448b9f1decda7245d23517b8e8dc99df2612f9c8bccb9b70499a36cb8a5c479d0efd1633e83af124f"},
+		{"Cre\u0300me bru\u0302le\u0301e 1 fi", "a5fd3b1b6d49cb96ec74a454daa5d2ec6654975a632755b448b9f1decda7245d23517b8e8dc99df2612f9c8bccb9b70499a36cb8a5c479d0efd1633e83af124f"},
+		{"한국어", "fdfef0428625690abd3d8413916681dac3673b4832e88af85f3e0907d4871399e031b8ebe71ea3de87e87b1cf960103487333bc4172b6c1e3f9d31e2f697f63f"},
+	}
+	for _, tt := range tests {
+		seed := generateSeedFromMnemonic(testMnemonic12, tt.passphrase)
+		if got := hex.EncodeToString(seed); got != tt.seed {
+			t.Errorf("generateSeedFromMnemonic(%q) = %s, want %s", tt.passphrase, got, tt.seed)
+		}
+	}
+}
+
+func TestMnemonicFromEntropy(t *testing.T) {
+	for _, v := range bip39Vectors {
+		entropy, err := hex.DecodeString(v.entropy)
+		if err != nil {
+			t.Fatalf("bad test entropy %q: %v", v.entropy, err)
+		}
+
+		mnemonic, err := MnemonicFromEntropy(entropy)
+		if err != nil {
+			t.Errorf("MnemonicFromEntropy(%s) failed: %v", v.entropy, err)
+			continue
+		}
+		if mnemonic != v.mnemonic {
+			t.Errorf("MnemonicFromEntropy(%s) = %q, want %q", v.entropy, mnemonic, v.mnemonic)
+		}
+
+		recovered, err := EntropyFromMnemonic(mnemonic)
+		if err != nil {
+			t.Errorf("EntropyFromMnemonic(%q) failed: %v", mnemonic, err)
+			continue
+		}
+		if hex.EncodeToString(recovered) != v.entropy {
+			t.Errorf("EntropyFromMnemonic(%q) = %x, want %s", mnemonic, recovered, v.entropy)
+		}
+	}
+
+	// Invalid entropy lengths
+	for _, n := range []int{0, 4, 12, 15, 17, 30, 36} {
+		_, err := MnemonicFromEntropy(make([]byte, n))
+		if !errors.Is(err, ErrInvalidEntropyLen) {
+			t.Errorf("MnemonicFromEntropy(%d bytes) = %v, want %v", n, err, ErrInvalidEntropyLen)
+		}
+	}
+
+	// Invalid mnemonics
+	invalid := map[string]error{
+		"abandon abandon abandon": ErrInvalidWordCount,
+		invalidMnemonic:           ErrInvalidWordCount,
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon":  ErrInvalidChecksum,
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandonn": ErrUnknownWord,
+	}
+	for mnemonic, want := range invalid {
+		if _, err := EntropyFromMnemonic(mnemonic); !errors.Is(err, want) {
+			t.Errorf("EntropyFromMnemonic(%q) = %v, want %v", mnemonic, err, want)
+		}
+	}
+}
+
 func TestGenerateMnemonic(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -108,9 +353,9 @@ func TestGenerateMnemonic(t *testing.T) {
 				return
 			}
 
-			// Validate generated mnemonic
-			if !validateMnemonic(mnemonic) {
-				t.Errorf("Generated mnemonic is invalid: %s", mnemonic)
+			// Validate generated mnemonic, including its checksum
+			if err := validateMnemonic(mnemonic); err != nil {
+				t.Errorf("Generated mnemonic is invalid: %s: %v", mnemonic, err)
 			}
 
 			// Check word count
@@ -157,6 +402,7 @@ func TestNewFromMnemonic(t *testing.T) {
 		{"Valid mnemonic with passphrase", testMnemonic12, &WalletConfig{Passphrase: "test"}, false},
 		{"Invalid mnemonic", invalidMnemonic, nil, true},
 		{"Empty mnemonic", "", nil, true},
+		{"Bad checksum", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", nil, true},
 	}
 
 	for _, tt := range tests {
@@ -312,6 +558,74 @@ func TestWalletDerivation(t *testing.T) {
 	}
 }
 
+func TestWalletDeriveAtPath(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// Derive wraps DeriveAtPath on the default base path
+	byIndex, err := wallet.Derive(9)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	byPath, err := wallet.DeriveAtPathString("m/44'/60'/0'/0/9")
+	if err != nil {
+		t.Fatalf("Failed to derive account at path: %v", err)
+	}
+	if byIndex.Address != byPath.Address {
+		t.Errorf("Derive(9) = %s, DeriveAtPath = %s", byIndex.Address.Hex(), byPath.Address.Hex())
+	}
+
+	tests := []struct {
+		path  string
+		index uint32
+	}{
+		{"m/44'/60'/1'/0/7", 7},
+		{"m/44'/60'/0'/1/0", 0},
+		{"m/44'/60'/3'/0/0", 0},
+		{"m/44'/60'/0'/5'", 5},
+	}
+
+	seen := map[Address]string{byPath.Address: byPath.Path}
+	for _, tt := range tests {
+		path := StrictParseDerivationPath(tt.path)
+		account, err := wallet.DeriveAtPath(path)
+		if err != nil {
+			t.Errorf("DeriveAtPath(%s) failed: %v", tt.path, err)
+			continue
+		}
+
+		if account.Path != tt.path {
+			t.Errorf("Account.Path = %s, want %s", account.Path, tt.path)
+		}
+		if account.Index != tt.index {
+			t.Errorf("Account.Index = %d, want %d", account.Index, tt.index)
+		}
+		if other, exists := seen[account.Address]; exists {
+			t.Errorf("Paths %s and %s produced the same address", other, tt.path)
+		}
+		seen[account.Address] = tt.path
+
+		// The recorded path is a copy
+		path[len(path)-1]++
+		recorded, err := wallet.Path(account.Address)
+		if err != nil {
+			t.Errorf("Path(%s) failed: %v", account.Address.Hex(), err)
+		} else if recorded.String() != tt.path {
+			t.Errorf("Path(%s) = %s, want %s", account.Address.Hex(), recorded, tt.path)
+		}
+	}
+
+	if _, err := wallet.DeriveAtPathString("m/44'/60'/x"); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("Expected ErrInvalidPath, got %v", err)
+	}
+	if _, err := wallet.Path(Address{}); err != ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+}
+
 func TestWalletKeyExtraction(t *testing.T) {
 	wallet, err := NewFromMnemonic(testMnemonic12, nil)
 	if err != nil {
@@ -412,6 +726,92 @@ func TestWalletDeterministicDerivation(t *testing.T) {
 	}
 }
 
+func TestWalletMasterKey(t *testing.T) {
+	wallet, err := NewFromMnemonic(testMnemonic12, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	// BIP-32 root key for the all-"abandon" mnemonic without a passphrase
+	expected := "xprv9s21ZrQH143K3GJpoapnV8SFfukcVBSfeCficPSGfubmSFDxo1kuHnLisriDvSnRRuL2Qrg5ggqHKNVpxR86QEC8w35uxmGoggxtQTPvfUu"
+	if got := wallet.masterKey.String(); got != expected {
+		t.Errorf("master key = %s, want %s", got, expected)
+	}
+}
+
+func TestWalletSecp256k1Keys(t *testing.T) {
+	wallet, err := NewFromMnemonic(testMnemonic12, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	if account.PublicKey.Curve != Secp256k1() {
+		t.Errorf("Expected secp256k1 key, got %s", account.PublicKey.Curve.Params().Name)
+	}
+
+	// Known keys for m/44'/60'/0'/0/0 of the all-"abandon" mnemonic
+	privateKeyHex, _ := wallet.GetPrivateKeyHex(account.Address)
+	if privateKeyHex != "1ab42cc412b618bdea3a599e3c9bae199ebf030895b039e9db1e30dafb12b727" {
+		t.Errorf("Unexpected private key: %s", privateKeyHex)
+	}
+	publicKeyHex, _ := wallet.GetPublicKeyHex(account.Address)
+	if publicKeyHex != "37b0bb7a8288d38ed49a524b5dc98cff3eb5ca824c9f9dc0dfdb3d9cd600f299a6179912b7451c09896c4098eca7ce6b2e58330672795e847c4d6af44e024230" {
+		t.Errorf("Unexpected public key: %s", publicKeyHex)
+	}
+}
+
+func TestWalletDemoAddresses(t *testing.T) {
+	// Sample accounts from docs/demos.md
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	tests := []struct {
+		index      uint32
+		address    string
+		privateKey string
+		publicKey  string
+	}{
+		{
+			0,
+			"0xC49926C4124cEe1cbA0Ea94Ea31a6c12318df947",
+			"63e21d10fd50155dbba0e7d3f7431a400b84b4c2ac1ee38872f82448fe3ecfb9",
+			"6005c86a6718f66221713a77073c41291cc3abbfcd03aa4955e9b2b50dbf7f9b6672dad0d46ade61e382f79888a73ea7899d9419becf1d6c9ec2087c1188fa18",
+		},
+		{9, "0x2d69B45301b9B3E01c4797C7a48BBc7e7F9b355b", "", ""},
+	}
+
+	for _, tt := range tests {
+		account, err := wallet.Derive(tt.index)
+		if err != nil {
+			t.Fatalf("Failed to derive account %d: %v", tt.index, err)
+		}
+
+		if got := account.Address.Hex(); got != tt.address {
+			t.Errorf("Account %d address = %s, want %s", tt.index, got, tt.address)
+		}
+		if tt.privateKey != "" {
+			if got, _ := wallet.GetPrivateKeyHex(account.Address); got != tt.privateKey {
+				t.Errorf("Account %d private key = %s, want %s", tt.index, got, tt.privateKey)
+			}
+		}
+		if tt.publicKey != "" {
+			if got, _ := wallet.GetPublicKeyHex(account.Address); got != tt.publicKey {
+				t.Errorf("Account %d public key = %s, want %s", tt.index, got, tt.publicKey)
+			}
+		}
+	}
+}
+
 func TestAddressTypes(t *testing.T) {
 	var addr Address
 	copy(addr[:], []byte("0123456789abcdef0123"))
@@ -451,7 +851,30 @@ func TestDerivationPath(t *testing.T) {
 	}{
 		{"Empty path", "", DerivationPath{}, false},
 		{"Root path", "m", DerivationPath{}, false},
-		{"Any other path", "m/44'/60'/0'/0/0", DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, 0}, false},
+		{"Default base path", "m/44'/60'/0'/0/0", DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, 0}, false},
+		{"Account and index", "m/44'/60'/1'/0/7", DerivationPath{0x8000002C, 0x8000003C, 0x80000001, 0, 7}, false},
+		{"Ledger Live path", "m/44'/60'/3'/0/0", DerivationPath{0x8000002C, 0x8000003C, 0x80000003, 0, 0}, false},
+		{"h hardened marker", "m/44h/60h/0h/1/2", DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 1, 2}, false},
+		{"H hardened marker", "m/0H/1", DerivationPath{0x80000000, 1}, false},
+		{"Maximum values", "m/2147483647'/2147483647", DerivationPath{0xFFFFFFFF, 0x7FFFFFFF}, false},
+		{"Surrounding whitespace", "  m/0/1 ", DerivationPath{0, 1}, false},
+		{"Relative path", "0/7", DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, 0, 7}, false},
+		{"Relative hardened path", "5'", DerivationPath{0x8000002C, 0x8000003C, 0x80000000, 0, 0x80000005}, false},
+		{"Trailing slash", "m/44'/", nil, true},
+		{"Empty segment", "m//0", nil, true},
+		{"Leading slash", "/0", nil, true},
+		{"Hardened overflow", "m/2147483648'", nil, true},
+		{"Normal overflow", "m/2147483648", nil, true},
+		{"Huge index", "m/99999999999", nil, true},
+		{"Negative index", "m/-1", nil, true},
+		{"Plus sign", "m/+1", nil, true},
+		{"Bare hardened marker", "m/'", nil, true},
+		{"Double hardened marker", "m/0''", nil, true},
+		{"Non-numeric segment", "m/44'/abc/0", nil, true},
+		{"Inner whitespace", "m/44'/ 60'", nil, true},
+		{"Bad root", "n/0", nil, true},
+		{"Root with suffix", "mm/0", nil, true},
+		{"Repeated root", "m/m/0", nil, true},
 	}
 
 	for _, tt := range tests {
@@ -459,8 +882,8 @@ func TestDerivationPath(t *testing.T) {
 			result, err := ParseDerivationPath(tt.path)
 
 			if tt.expectError {
-				if err == nil {
-					t.Errorf("Expected error but got none")
+				if !errors.Is(err, ErrInvalidPath) {
+					t.Errorf("Expected ErrInvalidPath, got %v (path %v)", err, result)
 				}
 				return
 			}
@@ -470,15 +893,50 @@ func TestDerivationPath(t *testing.T) {
 				return
 			}
 
-			// Note: The current implementation is simple and returns a fixed path
-			// This test validates the function doesn't crash and returns something
-			if result == nil {
-				t.Errorf("ParseDerivationPath returned nil")
+			if formatDerivationPath(result) != formatDerivationPath(tt.expectedPath) {
+				t.Errorf("ParseDerivationPath(%q) = %s, want %s", tt.path, result, tt.expectedPath)
 			}
 		})
 	}
 }
 
+func TestDerivationPathRoundTrip(t *testing.T) {
+	paths := []string{"m", "m/0", "m/44'/60'/0'/0/0", "m/44'/60'/1'/0/7", "m/0'/1/2'/2/1000000000", "m/2147483647'/2147483647"}
+	for _, path := range paths {
+		parsed, err := ParseDerivationPath(path)
+		if err != nil {
+			t.Errorf("ParseDerivationPath(%q) failed: %v", path, err)
+			continue
+		}
+		if formatted := formatDerivationPath(parsed); formatted != path {
+			t.Errorf("formatDerivationPath(ParseDerivationPath(%q)) = %q", path, formatted)
+		}
+		if parsed.String() != path {
+			t.Errorf("DerivationPath.String() = %q, want %q", parsed.String(), path)
+		}
+	}
+
+	// The error names the offending segment
+	_, err := ParseDerivationPath("m/44'/60'/x/0")
+	if err == nil || !strings.Contains(err.Error(), `segment 3 "x"`) {
+		t.Errorf("Expected error naming segment 3, got %v", err)
+	}
+}
+
+func TestStrictParseDerivationPath(t *testing.T) {
+	path := StrictParseDerivationPath("m/44'/60'/1'/0/7")
+	if path.String() != "m/44'/60'/1'/0/7" {
+		t.Errorf("StrictParseDerivationPath() = %s", path)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("StrictParseDerivationPath() did not panic on an invalid path")
+		}
+	}()
+	StrictParseDerivationPath("m/invalid")
+}
+
 func TestSecureClear(t *testing.T) {
 	data := []byte("sensitive data")
 	original := make([]byte, len(data))
@@ -560,8 +1018,8 @@ func TestNewMnemonic(t *testing.T) {
 		t.Errorf("NewMnemonic() failed: %v", err)
 	}
 
-	if !validateMnemonic(mnemonic) {
-		t.Errorf("NewMnemonic() generated invalid mnemonic: %s", mnemonic)
+	if err := validateMnemonic(mnemonic); err != nil {
+		t.Errorf("NewMnemonic() generated invalid mnemonic: %s: %v", mnemonic, err)
 	}
 
 	words := strings.Fields(mnemonic)
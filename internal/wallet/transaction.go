@@ -0,0 +1,502 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"simple-eth-hd-wallet/internal/rlp"
+)
+
+// Transaction types
+const (
+	// LegacyTxType is the untyped transaction of Frontier, Homestead and EIP-155
+	LegacyTxType = 0x00
+	// AccessListTxType is the EIP-2930 access list transaction
+	AccessListTxType = 0x01
+	// DynamicFeeTxType is the EIP-1559 dynamic fee transaction
+	DynamicFeeTxType = 0x02
+	// BlobTxType is the EIP-4844 blob transaction
+	BlobTxType = 0x03
+	// SetCodeTxType is the EIP-7702 set code transaction
+	SetCodeTxType = 0x04
+)
+
+// Transaction errors
+var (
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	ErrUnsignedTx         = errors.New("transaction is not signed")
+)
+
+// AccessTuple is an address and the storage slots a transaction accesses
+type AccessTuple struct {
+	Address     Address
+	StorageKeys [][HashLength]byte
+}
+
+// AccessList is the EIP-2930 list of pre-declared state accesses
+type AccessList []AccessTuple
+
+// Transaction is an Ethereum transaction. A nil To creates a contract and nil
+// amounts are treated as zero. For legacy transactions a nil ChainID selects
+// pre-EIP-155 (Homestead) signing without replay protection; typed
+// transactions always require a ChainID.
+//
+// GasPrice applies to legacy and access list transactions. Dynamic fee
+// and blob transactions use GasTipCap (maxPriorityFeePerGas) and GasFeeCap
+// (maxFeePerGas) instead. Blob transactions add BlobFeeCap
+// (maxFeePerBlobGas) and the versioned hashes of their blobs, and may carry
+// the blobs themselves in Sidecar. Set code transactions add the signed
+// EIP-7702 authorizations in AuthList.
+type Transaction struct {
+	Type       uint8
+	ChainID    *big.Int
+	Nonce      uint64
+	GasPrice   *big.Int
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *Address
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+	BlobFeeCap *big.Int
+	BlobHashes [][HashLength]byte
+	Sidecar    *BlobSidecar
+	AuthList   []SetCodeAuthorization
+
+	// Signature values; V follows the encoding of the transaction type
+	V, R, S *big.Int
+}
+
+// bigOrZero returns i, or zero when i is nil
+func bigOrZero(i *big.Int) *big.Int {
+	if i == nil {
+		return new(big.Int)
+	}
+	return i
+}
+
+// validate checks the fields that cannot be represented in the encoding of
+// the transaction type
+func (tx *Transaction) validate() error {
+	if tx.Type != BlobTxType && (tx.BlobFeeCap != nil || tx.BlobHashes != nil || tx.Sidecar != nil) {
+		return fmt.Errorf("%w: blob fields on a type %d transaction", ErrInvalidTransaction, tx.Type)
+	}
+	if tx.Type != SetCodeTxType && tx.AuthList != nil {
+		return fmt.Errorf("%w: authorization list on a type %d transaction", ErrInvalidTransaction, tx.Type)
+	}
+
+	switch tx.Type {
+	case LegacyTxType:
+		if tx.AccessList != nil {
+			return fmt.Errorf("%w: access list on a legacy transaction", ErrInvalidTransaction)
+		}
+		fallthrough
+	case AccessListTxType:
+		if tx.GasTipCap != nil || tx.GasFeeCap != nil {
+			return fmt.Errorf("%w: fee caps on a type %d transaction", ErrInvalidTransaction, tx.Type)
+		}
+	case DynamicFeeTxType, BlobTxType, SetCodeTxType:
+		if tx.GasPrice != nil {
+			return fmt.Errorf("%w: gas price on a type %d transaction", ErrInvalidTransaction, tx.Type)
+		}
+		if bigOrZero(tx.GasTipCap).Cmp(bigOrZero(tx.GasFeeCap)) > 0 {
+			return fmt.Errorf("%w: maxPriorityFeePerGas above maxFeePerGas", ErrInvalidTransaction)
+		}
+		switch tx.Type {
+		case BlobTxType:
+			if err := tx.validateBlobs(); err != nil {
+				return err
+			}
+		case SetCodeTxType:
+			if err := tx.validateAuthList(); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %d", ErrInvalidTransaction, tx.Type)
+	}
+
+	if tx.Type != LegacyTxType && tx.ChainID == nil {
+		return fmt.Errorf("%w: type %d transaction without chain ID", ErrInvalidTransaction, tx.Type)
+	}
+	for name, value := range map[string]*big.Int{
+		"chainId":              tx.ChainID,
+		"gasPrice":             tx.GasPrice,
+		"maxPriorityFeePerGas": tx.GasTipCap,
+		"maxFeePerGas":         tx.GasFeeCap,
+		"maxFeePerBlobGas":     tx.BlobFeeCap,
+		"value":                tx.Value,
+	} {
+		if value != nil && value.Sign() < 0 {
+			return fmt.Errorf("%w: negative %s", ErrInvalidTransaction, name)
+		}
+	}
+	return nil
+}
+
+// protected reports whether a legacy transaction uses EIP-155 replay
+// protection
+func (tx *Transaction) protected() bool {
+	return tx.ChainID != nil && tx.ChainID.Sign() > 0
+}
+
+// toField returns the encoding of the recipient: the address, or the empty
+// string for contract creation
+func (tx *Transaction) toField() []byte {
+	if tx.To == nil {
+		return []byte{}
+	}
+	return tx.To.Bytes()
+}
+
+// unsignedFields returns the fields of the transaction type that precede the
+// signature
+func (tx *Transaction) unsignedFields() []interface{} {
+	switch tx.Type {
+	case AccessListTxType:
+		return []interface{}{
+			tx.ChainID,
+			tx.Nonce,
+			bigOrZero(tx.GasPrice),
+			tx.Gas,
+			tx.toField(),
+			bigOrZero(tx.Value),
+			tx.Data,
+			tx.AccessList,
+		}
+	case DynamicFeeTxType, BlobTxType, SetCodeTxType:
+		fields := []interface{}{
+			tx.ChainID,
+			tx.Nonce,
+			bigOrZero(tx.GasTipCap),
+			bigOrZero(tx.GasFeeCap),
+			tx.Gas,
+			tx.toField(),
+			bigOrZero(tx.Value),
+			tx.Data,
+			tx.AccessList,
+		}
+		switch tx.Type {
+		case BlobTxType:
+			fields = append(fields, bigOrZero(tx.BlobFeeCap), tx.BlobHashes)
+		case SetCodeTxType:
+			fields = append(fields, tx.AuthList)
+		}
+		return fields
+	}
+	return []interface{}{
+		tx.Nonce,
+		bigOrZero(tx.GasPrice),
+		tx.Gas,
+		tx.toField(),
+		bigOrZero(tx.Value),
+		tx.Data,
+	}
+}
+
+// encode returns the RLP list of fields, prefixed with the type byte for
+// EIP-2718 typed transactions
+func (tx *Transaction) encode(fields []interface{}) ([]byte, error) {
+	encoded, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if tx.Type == LegacyTxType {
+		return encoded, nil
+	}
+	return append([]byte{tx.Type}, encoded...), nil
+}
+
+// SigningHash returns the hash that the sender signs. EIP-155 transactions
+// append [chainId, 0, 0] to the six legacy fields; typed transactions hash
+// the type byte followed by the RLP list of their unsigned fields.
+func (tx *Transaction) SigningHash() ([]byte, error) {
+	if err := tx.validate(); err != nil {
+		return nil, err
+	}
+
+	fields := tx.unsignedFields()
+	if tx.Type == LegacyTxType && tx.protected() {
+		fields = append(fields, tx.ChainID, uint64(0), uint64(0))
+	}
+
+	encoded, err := tx.encode(fields)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(encoded), nil
+}
+
+// IsSigned reports whether the transaction carries a signature
+func (tx *Transaction) IsSigned() bool {
+	return tx.V != nil && tx.R != nil && tx.S != nil
+}
+
+// WithSignature returns a copy of the transaction carrying a 65-byte
+// [R || S || V] signature as produced by SignHash, with V in {0, 1}
+func (tx *Transaction) WithSignature(sig []byte) (*Transaction, error) {
+	if len(sig) != SignatureLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidSignatureLength, len(sig))
+	}
+	recoveryID := sig[RecoveryIDOffset]
+	if recoveryID > 1 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRecoveryID, recoveryID)
+	}
+
+	signed := *tx
+	signed.R = new(big.Int).SetBytes(sig[:32])
+	signed.S = new(big.Int).SetBytes(sig[32:64])
+
+	// Typed transactions carry the y-parity directly. Legacy V is 27/28, or
+	// chainId·2 + 35/36 under EIP-155.
+	switch {
+	case tx.Type != LegacyTxType:
+		signed.V = big.NewInt(int64(recoveryID))
+	case tx.protected():
+		signed.V = new(big.Int).Lsh(tx.ChainID, 1)
+		signed.V.Add(signed.V, big.NewInt(35+int64(recoveryID)))
+	default:
+		signed.V = big.NewInt(27 + int64(recoveryID))
+	}
+	return &signed, nil
+}
+
+// recoveryID returns the signature recovery id encoded in V
+func (tx *Transaction) recoveryID() (byte, error) {
+	v := new(big.Int).Set(tx.V)
+	if tx.Type == LegacyTxType {
+		if tx.protected() {
+			v.Sub(v, new(big.Int).Lsh(tx.ChainID, 1))
+			v.Sub(v, big.NewInt(35))
+		} else {
+			v.Sub(v, big.NewInt(27))
+		}
+	}
+	if v.Sign() < 0 || v.Cmp(big.NewInt(1)) > 0 {
+		return 0, fmt.Errorf("%w: V = %v", ErrInvalidRecoveryID, tx.V)
+	}
+	return byte(v.Uint64()), nil
+}
+
+// Sender recovers the address that signed the transaction. High-S
+// signatures, invalid since Homestead, are rejected.
+func (tx *Transaction) Sender() (Address, error) {
+	if !tx.IsSigned() {
+		return Address{}, ErrUnsignedTx
+	}
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return Address{}, err
+	}
+	recoveryID, err := tx.recoveryID()
+	if err != nil {
+		return Address{}, err
+	}
+	sig, err := signatureBytes(tx.R, tx.S, recoveryID)
+	if err != nil {
+		return Address{}, err
+	}
+	return RecoverAddressStrict(hash, sig)
+}
+
+// signatureBytes returns the 65-byte [R || S || V] form of a signature held
+// as integers
+func signatureBytes(r, s *big.Int, recoveryID byte) ([]byte, error) {
+	if r.Sign() < 0 || s.Sign() < 0 || r.BitLen() > 256 || s.BitLen() > 256 {
+		return nil, ErrInvalidSignature
+	}
+
+	sig := make([]byte, SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[RecoveryIDOffset] = recoveryID
+	return sig, nil
+}
+
+// signedFields returns the fields of a valid signed transaction
+func (tx *Transaction) signedFields() ([]interface{}, error) {
+	if err := tx.validate(); err != nil {
+		return nil, err
+	}
+	if !tx.IsSigned() {
+		return nil, ErrUnsignedTx
+	}
+	return append(tx.unsignedFields(), tx.V, tx.R, tx.S), nil
+}
+
+// MarshalBinary returns the signed transaction in its network encoding, the
+// form accepted by eth_sendRawTransaction. A blob transaction with a sidecar
+// is wrapped together with its blobs, commitments and proofs.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	fields, err := tx.signedFields()
+	if err != nil {
+		return nil, err
+	}
+	if tx.Sidecar != nil {
+		fields = append([]interface{}{fields}, tx.Sidecar.fields()...)
+	}
+	return tx.encode(fields)
+}
+
+// CanonicalBinary returns the signed transaction in its canonical encoding,
+// the form included in blocks and hashed. It differs from MarshalBinary only
+// for blob transactions carrying a sidecar.
+func (tx *Transaction) CanonicalBinary() ([]byte, error) {
+	fields, err := tx.signedFields()
+	if err != nil {
+		return nil, err
+	}
+	return tx.encode(fields)
+}
+
+// UnmarshalBinary decodes a signed transaction from its network or canonical
+// encoding: an RLP list for legacy transactions, or a type byte followed by
+// the RLP payload for typed transactions. The chain ID of a legacy
+// transaction is taken from V, and a blob transaction in network form keeps
+// its sidecar.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return fmt.Errorf("%w: empty input", ErrInvalidTransaction)
+	}
+
+	var decoded Transaction
+	payload := b
+	switch {
+	case b[0] >= 0xC0:
+		decoded.Type = LegacyTxType
+	case b[0] == LegacyTxType:
+		// Legacy transactions have no type byte; 0x00 is not a valid envelope
+		return fmt.Errorf("%w: legacy transaction with a type prefix", ErrInvalidTransaction)
+	case b[0] < 0x80:
+		decoded.Type = b[0]
+		payload = b[1:]
+	default:
+		return fmt.Errorf("%w: not a transaction envelope", ErrInvalidTransaction)
+	}
+
+	var to []byte
+	var targets []interface{}
+	switch decoded.Type {
+	case LegacyTxType:
+		targets = []interface{}{&decoded.Nonce, &decoded.GasPrice, &decoded.Gas, &to, &decoded.Value, &decoded.Data}
+	case AccessListTxType:
+		targets = []interface{}{&decoded.ChainID, &decoded.Nonce, &decoded.GasPrice, &decoded.Gas, &to, &decoded.Value, &decoded.Data, &decoded.AccessList}
+	case DynamicFeeTxType, BlobTxType, SetCodeTxType:
+		targets = []interface{}{&decoded.ChainID, &decoded.Nonce, &decoded.GasTipCap, &decoded.GasFeeCap, &decoded.Gas, &to, &decoded.Value, &decoded.Data, &decoded.AccessList}
+		switch decoded.Type {
+		case BlobTxType:
+			var err error
+			if payload, decoded.Sidecar, err = splitBlobWrapper(payload); err != nil {
+				return err
+			}
+			targets = append(targets, &decoded.BlobFeeCap, &decoded.BlobHashes)
+		case SetCodeTxType:
+			targets = append(targets, &decoded.AuthList)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %d", ErrInvalidTransaction, decoded.Type)
+	}
+	targets = append(targets, &decoded.V, &decoded.R, &decoded.S)
+	if err := decodeFields(payload, targets); err != nil {
+		return err
+	}
+
+	switch len(to) {
+	case 0:
+	case AddressLength:
+		decoded.To = new(Address)
+		copy(decoded.To[:], to)
+	default:
+		return fmt.Errorf("%w: recipient of %d bytes", ErrInvalidTransaction, len(to))
+	}
+
+	if decoded.Type == LegacyTxType && decoded.V.Cmp(big.NewInt(35)) >= 0 {
+		decoded.ChainID = new(big.Int).Sub(decoded.V, big.NewInt(35))
+		decoded.ChainID.Rsh(decoded.ChainID, 1)
+	}
+	if _, err := decoded.recoveryID(); err != nil {
+		return err
+	}
+	if err := decoded.validate(); err != nil {
+		return err
+	}
+
+	*tx = decoded
+	return nil
+}
+
+// decodeFields decodes the RLP list b into targets, which must match the
+// number of list items
+func decodeFields(b []byte, targets []interface{}) error {
+	var items []rlp.RawValue
+	if err := rlp.DecodeBytes(b, &items); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if len(items) != len(targets) {
+		return fmt.Errorf("%w: %d fields, want %d", ErrInvalidTransaction, len(items), len(targets))
+	}
+	for i, item := range items {
+		if err := rlp.DecodeBytes(item, targets[i]); err != nil {
+			return fmt.Errorf("%w: field %d: %v", ErrInvalidTransaction, i, err)
+		}
+	}
+	return nil
+}
+
+// ParseRawTransaction decodes a signed transaction from its hex network
+// encoding, with or without a 0x prefix
+func ParseRawTransaction(s string) (*Transaction, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	tx := new(Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Hash returns the transaction hash, keccak256 of the canonical encoding
+func (tx *Transaction) Hash() ([]byte, error) {
+	encoded, err := tx.CanonicalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(encoded), nil
+}
+
+// SignTransaction signs a transaction with the key of a derived account and
+// returns the signed copy
+func (w *SimpleWallet) SignTransaction(address Address, tx *Transaction) (*Transaction, error) {
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := w.SignHash(address, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return tx.WithSignature(sig)
+}
+
+// SignTx signs a transaction with the key of a derived account and returns
+// the 0x-prefixed raw transaction hex ready for eth_sendRawTransaction
+func (w *SimpleWallet) SignTx(address Address, tx *Transaction) (string, error) {
+	signed, err := w.SignTransaction(address, tx)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,350 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// quantity is a JSON integer written as a 0x-prefixed hex string, a decimal
+// string or a plain number. It marshals as hex like the Ethereum JSON-RPC.
+type quantity big.Int
+
+// UnmarshalJSON implements json.Unmarshaler
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	var ok bool
+	if digits := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"); digits != s {
+		_, ok = (*big.Int)(q).SetString(digits, 16)
+	} else {
+		_, ok = (*big.Int)(q).SetString(s, 10)
+	}
+	if !ok || (*big.Int)(q).Sign() < 0 {
+		return fmt.Errorf("invalid quantity %s", data)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (q *quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + (*big.Int)(q).Text(16))
+}
+
+// newQuantity returns i as a quantity, or nil when i is nil
+func newQuantity(i *big.Int) *quantity {
+	return (*quantity)(i)
+}
+
+// bigInt returns q as a big.Int, or nil when q is nil
+func (q *quantity) bigInt() *big.Int {
+	return (*big.Int)(q)
+}
+
+// uint64Value returns q as a uint64 and reports a missing or out of range
+// value for the named field
+func (q *quantity) uint64Value(name string) (uint64, error) {
+	if q == nil {
+		return 0, fmt.Errorf("%w: missing %s", ErrInvalidTransaction, name)
+	}
+	if !q.bigInt().IsUint64() {
+		return 0, fmt.Errorf("%w: %s out of range", ErrInvalidTransaction, name)
+	}
+	return q.bigInt().Uint64(), nil
+}
+
+// hexBytes is a byte string written as 0x-prefixed hex
+type hexBytes []byte
+
+// MarshalText implements encoding.TextMarshaler
+func (b hexBytes) MarshalText() ([]byte, error) {
+	return []byte("0x" + hex.EncodeToString(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *hexBytes) UnmarshalText(text []byte) error {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(string(text), "0x"), "0X"))
+	if err != nil {
+		return fmt.Errorf("invalid hex %q: %v", text, err)
+	}
+	*b = decoded
+	return nil
+}
+
+// fixedBytes copies b into dst and reports a length mismatch for the named
+// field
+func fixedBytes(dst []byte, b hexBytes, name string) error {
+	if len(b) != len(dst) {
+		return fmt.Errorf("%w: %s has %d bytes, want %d", ErrInvalidTransaction, name, len(b), len(dst))
+	}
+	copy(dst, b)
+	return nil
+}
+
+// accessTupleJSON is the JSON form of an AccessTuple
+type accessTupleJSON struct {
+	Address     Address    `json:"address"`
+	StorageKeys []hexBytes `json:"storageKeys"`
+}
+
+// authorizationJSON is the JSON form of a SetCodeAuthorization
+type authorizationJSON struct {
+	ChainID *quantity `json:"chainId"`
+	Address Address   `json:"address"`
+	Nonce   *quantity `json:"nonce"`
+	YParity *quantity `json:"yParity,omitempty"`
+	R       *quantity `json:"r,omitempty"`
+	S       *quantity `json:"s,omitempty"`
+}
+
+// transactionJSON is the JSON form of a Transaction, using the field names
+// of the Ethereum JSON-RPC. Input may also be given as data. From is
+// accepted for callers that check it against the signer, but is not part of
+// the transaction.
+type transactionJSON struct {
+	Type                 *quantity           `json:"type,omitempty"`
+	ChainID              *quantity           `json:"chainId,omitempty"`
+	From                 *Address            `json:"from,omitempty"`
+	Nonce                *quantity           `json:"nonce"`
+	GasPrice             *quantity           `json:"gasPrice,omitempty"`
+	MaxPriorityFeePerGas *quantity           `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerGas         *quantity           `json:"maxFeePerGas,omitempty"`
+	Gas                  *quantity           `json:"gas"`
+	To                   *Address            `json:"to"`
+	Value                *quantity           `json:"value"`
+	Input                hexBytes            `json:"input"`
+	Data                 hexBytes            `json:"data,omitempty"`
+	AccessList           []accessTupleJSON   `json:"accessList,omitempty"`
+	MaxFeePerBlobGas     *quantity           `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []hexBytes          `json:"blobVersionedHashes,omitempty"`
+	Blobs                []hexBytes          `json:"blobs,omitempty"`
+	Commitments          []hexBytes          `json:"commitments,omitempty"`
+	Proofs               []hexBytes          `json:"proofs,omitempty"`
+	AuthorizationList    []authorizationJSON `json:"authorizationList,omitempty"`
+	V                    *quantity           `json:"v,omitempty"`
+	R                    *quantity           `json:"r,omitempty"`
+	S                    *quantity           `json:"s,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler with the Ethereum JSON-RPC field
+// names and hex quantities
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	enc := transactionJSON{
+		Type:                 newQuantity(big.NewInt(int64(tx.Type))),
+		ChainID:              newQuantity(tx.ChainID),
+		Nonce:                newQuantity(new(big.Int).SetUint64(tx.Nonce)),
+		GasPrice:             newQuantity(tx.GasPrice),
+		MaxPriorityFeePerGas: newQuantity(tx.GasTipCap),
+		MaxFeePerGas:         newQuantity(tx.GasFeeCap),
+		Gas:                  newQuantity(new(big.Int).SetUint64(tx.Gas)),
+		To:                   tx.To,
+		Value:                newQuantity(bigOrZero(tx.Value)),
+		Input:                hexBytes(tx.Data),
+		MaxFeePerBlobGas:     newQuantity(tx.BlobFeeCap),
+		V:                    newQuantity(tx.V),
+		R:                    newQuantity(tx.R),
+		S:                    newQuantity(tx.S),
+	}
+	if enc.Input == nil {
+		enc.Input = hexBytes{}
+	}
+
+	for _, tuple := range tx.AccessList {
+		keys := make([]hexBytes, len(tuple.StorageKeys))
+		for i := range tuple.StorageKeys {
+			keys[i] = tuple.StorageKeys[i][:]
+		}
+		enc.AccessList = append(enc.AccessList, accessTupleJSON{Address: tuple.Address, StorageKeys: keys})
+	}
+	if tx.AccessList != nil && enc.AccessList == nil {
+		enc.AccessList = []accessTupleJSON{}
+	}
+
+	for i := range tx.BlobHashes {
+		enc.BlobVersionedHashes = append(enc.BlobVersionedHashes, tx.BlobHashes[i][:])
+	}
+	if tx.Sidecar != nil {
+		for i := range tx.Sidecar.Blobs {
+			enc.Blobs = append(enc.Blobs, tx.Sidecar.Blobs[i][:])
+		}
+		for i := range tx.Sidecar.Commitments {
+			enc.Commitments = append(enc.Commitments, tx.Sidecar.Commitments[i][:])
+		}
+		for i := range tx.Sidecar.Proofs {
+			enc.Proofs = append(enc.Proofs, tx.Sidecar.Proofs[i][:])
+		}
+	}
+
+	for _, auth := range tx.AuthList {
+		enc.AuthorizationList = append(enc.AuthorizationList, authorizationJSON{
+			ChainID: newQuantity(bigOrZero(auth.ChainID)),
+			Address: auth.Address,
+			Nonce:   newQuantity(new(big.Int).SetUint64(auth.Nonce)),
+			YParity: newQuantity(big.NewInt(int64(auth.V))),
+			R:       newQuantity(auth.R),
+			S:       newQuantity(auth.S),
+		})
+	}
+
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Unknown fields are rejected and
+// nonce and gas are required, so that a typo cannot silently change what is
+// signed. Without a type field the type is inferred from the fee and list
+// fields present. Blob versioned hashes default to the hashes of the
+// commitments, and the sidecar version follows from the number of proofs.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	var dec transactionJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&dec); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	var decoded Transaction
+	var err error
+
+	switch {
+	case dec.Type != nil:
+		txType, err := dec.Type.uint64Value("type")
+		if err != nil {
+			return err
+		}
+		if txType > SetCodeTxType {
+			return fmt.Errorf("%w: unsupported type %d", ErrInvalidTransaction, txType)
+		}
+		decoded.Type = uint8(txType)
+	case dec.AuthorizationList != nil:
+		decoded.Type = SetCodeTxType
+	case dec.BlobVersionedHashes != nil || dec.Blobs != nil:
+		decoded.Type = BlobTxType
+	case dec.MaxFeePerGas != nil || dec.MaxPriorityFeePerGas != nil:
+		decoded.Type = DynamicFeeTxType
+	case dec.AccessList != nil:
+		decoded.Type = AccessListTxType
+	}
+
+	if decoded.Nonce, err = dec.Nonce.uint64Value("nonce"); err != nil {
+		return err
+	}
+	if decoded.Gas, err = dec.Gas.uint64Value("gas"); err != nil {
+		return err
+	}
+	decoded.ChainID = dec.ChainID.bigInt()
+	decoded.GasPrice = dec.GasPrice.bigInt()
+	decoded.GasTipCap = dec.MaxPriorityFeePerGas.bigInt()
+	decoded.GasFeeCap = dec.MaxFeePerGas.bigInt()
+	decoded.BlobFeeCap = dec.MaxFeePerBlobGas.bigInt()
+	decoded.To = dec.To
+	decoded.Value = dec.Value.bigInt()
+
+	if dec.Input != nil && dec.Data != nil && !bytes.Equal(dec.Input, dec.Data) {
+		return fmt.Errorf("%w: input and data differ", ErrInvalidTransaction)
+	}
+	decoded.Data = dec.Input
+	if decoded.Data == nil {
+		decoded.Data = dec.Data
+	}
+
+	if dec.AccessList != nil {
+		decoded.AccessList = make(AccessList, len(dec.AccessList))
+		for i, tuple := range dec.AccessList {
+			decoded.AccessList[i].Address = tuple.Address
+			decoded.AccessList[i].StorageKeys = make([][HashLength]byte, len(tuple.StorageKeys))
+			for j, key := range tuple.StorageKeys {
+				if err := fixedBytes(decoded.AccessList[i].StorageKeys[j][:], key, "storage key"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := dec.decodeBlobs(&decoded); err != nil {
+		return err
+	}
+
+	for _, auth := range dec.AuthorizationList {
+		nonce, err := auth.Nonce.uint64Value("authorization nonce")
+		if err != nil {
+			return err
+		}
+		parsed := SetCodeAuthorization{
+			ChainID: bigOrZero(auth.ChainID.bigInt()),
+			Address: auth.Address,
+			Nonce:   nonce,
+			R:       auth.R.bigInt(),
+			S:       auth.S.bigInt(),
+		}
+		if auth.YParity != nil {
+			if !auth.YParity.bigInt().IsUint64() || auth.YParity.bigInt().Uint64() > 1 {
+				return fmt.Errorf("%w: authorization y-parity %v", ErrInvalidRecoveryID, auth.YParity.bigInt())
+			}
+			parsed.V = uint8(auth.YParity.bigInt().Uint64())
+		}
+		decoded.AuthList = append(decoded.AuthList, parsed)
+	}
+
+	if (dec.V == nil) != (dec.R == nil) || (dec.V == nil) != (dec.S == nil) {
+		return fmt.Errorf("%w: incomplete signature", ErrInvalidTransaction)
+	}
+	decoded.V, decoded.R, decoded.S = dec.V.bigInt(), dec.R.bigInt(), dec.S.bigInt()
+
+	if err := decoded.validate(); err != nil {
+		return err
+	}
+	*tx = decoded
+	return nil
+}
+
+// decodeBlobs sets the blob hashes and sidecar of tx from their JSON form
+func (dec *transactionJSON) decodeBlobs(tx *Transaction) error {
+	for _, hash := range dec.BlobVersionedHashes {
+		var h [HashLength]byte
+		if err := fixedBytes(h[:], hash, "blob versioned hash"); err != nil {
+			return err
+		}
+		tx.BlobHashes = append(tx.BlobHashes, h)
+	}
+	if dec.Blobs == nil && dec.Commitments == nil && dec.Proofs == nil {
+		return nil
+	}
+
+	sidecar := &BlobSidecar{
+		Blobs:       make([]Blob, len(dec.Blobs)),
+		Commitments: make([]KZGCommitment, len(dec.Commitments)),
+		Proofs:      make([]KZGProof, len(dec.Proofs)),
+	}
+	for i, blob := range dec.Blobs {
+		if err := fixedBytes(sidecar.Blobs[i][:], blob, "blob"); err != nil {
+			return err
+		}
+	}
+	for i, commitment := range dec.Commitments {
+		if err := fixedBytes(sidecar.Commitments[i][:], commitment, "commitment"); err != nil {
+			return err
+		}
+	}
+	for i, proof := range dec.Proofs {
+		if err := fixedBytes(sidecar.Proofs[i][:], proof, "proof"); err != nil {
+			return err
+		}
+	}
+	if len(sidecar.Blobs) > 0 && len(sidecar.Proofs) == len(sidecar.Blobs)*CellsPerExtBlob {
+		sidecar.Version = BlobSidecarVersion1
+	}
+
+	if tx.BlobHashes == nil {
+		tx.BlobHashes = sidecar.BlobHashes()
+	}
+	tx.Sidecar = sidecar
+	return nil
+}
@@ -0,0 +1,165 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTransactionJSONSigningHash(t *testing.T) {
+	// The JSON forms of the transaction vectors
+	tests := []struct {
+		name    string
+		json    string
+		txType  uint8
+		sigHash string
+	}{
+		{"eip155", `{
+			"chainId": 1, "nonce": "9", "gasPrice": "20000000000", "gas": "0x5208",
+			"to": "0x3535353535353535353535353535353535353535", "value": "1000000000000000000"
+		}`, LegacyTxType, "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53"},
+		{"access list", `{
+			"type": "0x1", "chainId": "0x1", "nonce": "0x3", "gasPrice": "0x1", "gas": "0x61a8",
+			"to": "0xb94f5374fce5edbc8e2a8697c15331677e6ebf0b", "value": "0xa", "data": "0x5544", "accessList": []
+		}`, AccessListTxType, "49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3"},
+		{"dynamic fee", `{
+			"chainId": "0x1", "nonce": "0x0", "maxPriorityFeePerGas": "1500000000", "maxFeePerGas": "30000000000",
+			"gas": 21000, "to": "0x3535353535353535353535353535353535353535", "value": "0xde0b6b3a7640000",
+			"input": "0x",
+			"accessList": [{
+				"address": "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae",
+				"storageKeys": [
+					"0x0000000000000000000000000000000000000000000000000000000000000003",
+					"0x0000000000000000000000000000000000000000000000000000000000000007"
+				]
+			}]
+		}`, DynamicFeeTxType, "6d96c50918b7221b216af9b314fb0d8188121d860341587a7d8a6b34682e53da"},
+		{"blob", `{
+			"chainId": "0x1", "nonce": "0x0", "maxPriorityFeePerGas": "0x3b9aca00", "maxFeePerGas": "0x6fc23ac00",
+			"gas": "0x5208", "to": "0x3535353535353535353535353535353535353535", "value": "0x0",
+			"maxFeePerBlobGas": "0x3b9aca00",
+			"blobVersionedHashes": ["0x010657f37554c781402a22917dee2f75def7ab966d7b770905398eba3c444014"]
+		}`, BlobTxType, "2cd9b9d1643804245c897695fab258b7f0fb3755d39b1bc44b71bcf919720b5c"},
+		{"set code", `{
+			"chainId": "0x1", "nonce": "0x8", "maxPriorityFeePerGas": "0x3b9aca00", "maxFeePerGas": "0x6fc23ac00",
+			"gas": "0x186a0", "to": "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", "value": "0x0",
+			"authorizationList": [{
+				"chainId": "0x1", "address": "0x3535353535353535353535353535353535353535", "nonce": "0x7",
+				"yParity": "0x0",
+				"r": "0xada169c25b37d5ec7657b637677f2cc29cba28c40bfd0ac50bececf91263edd5",
+				"s": "0x56da4e2c712f3431bcb8728e66bb387b0e177ea92484d366c055c7ec6b56969a"
+			}]
+		}`, SetCodeTxType, "102642b73ee44d74a488eb5e42e2327f657f7a97c918acaa8135a2aef2417804"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tx Transaction
+			if err := json.Unmarshal([]byte(tt.json), &tx); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if tx.Type != tt.txType {
+				t.Errorf("Type = %d, want %d", tx.Type, tt.txType)
+			}
+			hash, err := tx.SigningHash()
+			if err != nil {
+				t.Fatalf("SigningHash failed: %v", err)
+			}
+			if got := hex.EncodeToString(hash); got != tt.sigHash {
+				t.Errorf("SigningHash = %s, want %s", got, tt.sigHash)
+			}
+
+			// Marshalling and parsing again keeps the signing hash
+			encoded, err := json.Marshal(&tx)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var again Transaction
+			if err := json.Unmarshal(encoded, &again); err != nil {
+				t.Fatalf("Unmarshal(%s) failed: %v", encoded, err)
+			}
+			if hash2, _ := again.SigningHash(); !bytes.Equal(hash, hash2) {
+				t.Errorf("Round trip through %s changed the signing hash", encoded)
+			}
+		})
+	}
+}
+
+func TestTransactionJSONSigned(t *testing.T) {
+	wallet, account := newTestWallet(t)
+
+	// Blob hashes follow from the sidecar commitments
+	unsigned := `{"chainId": "0x1", "nonce": "0x1", "gas": "0x5208", "maxFeePerGas": "0x2", "maxFeePerBlobGas": "0x1",
+		"to": "0x3535353535353535353535353535353535353535",
+		"blobs": ["0x` + strings.Repeat("00", BlobLength) + `"],
+		"commitments": ["0xc0` + strings.Repeat("00", KZGCommitmentLength-1) + `"],
+		"proofs": ["0xc0` + strings.Repeat("00", KZGProofLength-1) + `"]}`
+	var tx Transaction
+	if err := json.Unmarshal([]byte(unsigned), &tx); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if tx.Type != BlobTxType || tx.Sidecar == nil || tx.Sidecar.Version != BlobSidecarVersion0 ||
+		len(tx.BlobHashes) != 1 || hex.EncodeToString(tx.BlobHashes[0][:]) != emptyBlobHash {
+		t.Fatalf("Decoded %+v", tx)
+	}
+
+	signed, err := wallet.SignTransaction(account.Address, &tx)
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+	encoded, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded Transaction
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	raw, _ := signed.MarshalBinary()
+	again, err := decoded.MarshalBinary()
+	if err != nil || !bytes.Equal(raw, again) {
+		t.Errorf("Signed transaction does not round trip through JSON: %v", err)
+	}
+	if sender, err := decoded.Sender(); err != nil || sender != account.Address {
+		t.Errorf("Sender = %s, %v; want %s", sender.Hex(), err, account.Address.Hex())
+	}
+}
+
+func TestTransactionJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing nonce", `{"gas": "0x5208"}`},
+		{"missing gas", `{"nonce": "0x0"}`},
+		{"unknown field", `{"nonce": "0x0", "gas": "0x5208", "maxFeePerGass": "0x1"}`},
+		{"negative value", `{"nonce": "0x0", "gas": "0x5208", "value": "-1"}`},
+		{"float", `{"nonce": "0x0", "gas": 2.1e4}`},
+		{"empty hex quantity", `{"nonce": "0x", "gas": "0x5208"}`},
+		{"gas overflow", `{"nonce": "0x0", "gas": "0x10000000000000000"}`},
+		{"unknown type", `{"type": "0x7f", "nonce": "0x0", "gas": "0x5208"}`},
+		{"bad data", `{"nonce": "0x0", "gas": "0x5208", "data": "0xzz"}`},
+		{"input and data differ", `{"nonce": "0x0", "gas": "0x5208", "input": "0x01", "data": "0x02"}`},
+		{"short storage key", `{"nonce": "0x0", "gas": "0x5208", "chainId": "0x1", "accessList": [{"address": "0x3535353535353535353535353535353535353535", "storageKeys": ["0x01"]}]}`},
+		{"bad checksum", `{"nonce": "0x0", "gas": "0x5208", "to": "0x9d8a62f656a8d1615c1294fd71e9CFb3E4855A4F"}`},
+		{"incomplete signature", `{"nonce": "0x0", "gas": "0x5208", "v": "0x1b"}`},
+		{"type without chain ID", `{"type": "0x2", "nonce": "0x0", "gas": "0x5208"}`},
+		{"y-parity", `{"chainId": "0x1", "nonce": "0x0", "gas": "0x5208", "to": "0x3535353535353535353535353535353535353535", "authorizationList": [{"chainId": "0x1", "address": "0x3535353535353535353535353535353535353535", "nonce": "0x0", "yParity": "0x2"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tx Transaction
+			if err := json.Unmarshal([]byte(tt.json), &tx); err == nil {
+				t.Errorf("Unmarshal(%s) succeeded, want error", tt.json)
+			}
+		})
+	}
+
+	var tx Transaction
+	if err := json.Unmarshal([]byte(`{"nonce": "0x0"}`), &tx); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("Expected ErrInvalidTransaction, got %v", err)
+	}
+}
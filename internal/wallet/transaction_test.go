@@ -0,0 +1,488 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"simple-eth-hd-wallet/internal/rlp"
+)
+
+func mustBigInt(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("invalid integer " + s)
+	}
+	return i
+}
+
+func addressPtr(s string) *Address {
+	a := HexToAddress(s)
+	return &a
+}
+
+// newTestWallet returns a wallet for demoMnemonic with account 0 derived
+func newTestWallet(t *testing.T) (*SimpleWallet, *Account) {
+	t.Helper()
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	t.Cleanup(func() { wallet.Close() })
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	return wallet, account
+}
+
+func TestLegacyTxEIP155Vector(t *testing.T) {
+	// The example from the EIP-155 specification
+	tx := &Transaction{
+		Type:     LegacyTxType,
+		ChainID:  big.NewInt(1),
+		Nonce:    9,
+		GasPrice: mustBigInt("20000000000"),
+		Gas:      21000,
+		To:       addressPtr("0x3535353535353535353535353535353535353535"),
+		Value:    mustBigInt("1000000000000000000"),
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	key := mustBigInt("0x4646464646464646464646464646464646464646464646464646464646464646")
+	sig, err := signHash(key, hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+
+	if signed.V.Int64() != 37 {
+		t.Errorf("V = %v, want 37", signed.V)
+	}
+	if want := mustBigInt("18515461264373351373200002665853028612451056578545711640558177340181847433846"); signed.R.Cmp(want) != 0 {
+		t.Errorf("R = %v, want %v", signed.R, want)
+	}
+	if want := mustBigInt("46948507304638947509940763649030358759909902576025900602547168820602576006531"); signed.S.Cmp(want) != 0 {
+		t.Errorf("S = %v, want %v", signed.S, want)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	want := "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025" +
+		"a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Errorf("MarshalBinary = %s, want %s", got, want)
+	}
+
+	// The original transaction is left unsigned
+	if tx.IsSigned() {
+		t.Error("WithSignature modified the original transaction")
+	}
+}
+
+func TestLegacyTxHomesteadDemo(t *testing.T) {
+	// demo3-sign in docs/demos.md: a pre-EIP-155 transfer from account 0
+	wallet, account := newTestWallet(t)
+	tx := &Transaction{
+		Nonce:    0,
+		GasPrice: big.NewInt(21000000000),
+		Gas:      21000,
+		To:       &Address{},
+		Value:    mustBigInt("1000000000000000000"),
+	}
+
+	signed, err := wallet.SignTransaction(account.Address, tx)
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+
+	if signed.V.Int64() != 27 {
+		t.Errorf("V = %v, want 27", signed.V)
+	}
+	if want := mustBigInt("34405166580762396054881948095668280144114812929766777744840143175291345694076"); signed.R.Cmp(want) != 0 {
+		t.Errorf("R = %v, want %v", signed.R, want)
+	}
+	if want := mustBigInt("35876182893985365337785111608140175101791577417143941897988905965773505549184"); signed.S.Cmp(want) != 0 {
+		t.Errorf("S = %v, want %v", signed.S, want)
+	}
+
+	// SignTx returns the same encoding as hex
+	raw, err := wallet.SignTx(account.Address, tx)
+	if err != nil {
+		t.Fatalf("SignTx failed: %v", err)
+	}
+	encoded, _ := signed.MarshalBinary()
+	if raw != "0x"+hex.EncodeToString(encoded) {
+		t.Errorf("SignTx = %s, want 0x%x", raw, encoded)
+	}
+}
+
+func TestLegacyTxEncoding(t *testing.T) {
+	wallet, account := newTestWallet(t)
+
+	// Contract creation with data on chain 11155111
+	tx := &Transaction{
+		ChainID:  big.NewInt(11155111),
+		Nonce:    300,
+		GasPrice: big.NewInt(1),
+		Gas:      500000,
+		Data:     []byte{0x60, 0x80, 0x60, 0x40},
+	}
+	signed, err := wallet.SignTransaction(account.Address, tx)
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw, &fields); err != nil {
+		t.Fatalf("Signed transaction is not an RLP list: %v", err)
+	}
+	if len(fields) != 9 {
+		t.Fatalf("Signed transaction has %d fields, want 9", len(fields))
+	}
+	wantFields := []string{"82012c", "01", "8307a120", "80", "80", "8460806040"}
+	for i, want := range wantFields {
+		if got := hex.EncodeToString(fields[i]); got != want {
+			t.Errorf("Field %d = %s, want %s", i, got, want)
+		}
+	}
+
+	// V = chainId·2 + 35 + recovery id
+	v := new(big.Int).Sub(signed.V, big.NewInt(2*11155111+35))
+	if v.Sign() < 0 || v.Cmp(big.NewInt(1)) > 0 {
+		t.Errorf("V = %v is not an EIP-155 value for chain 11155111", signed.V)
+	}
+
+	// The signature recovers the sender
+	hash, _ := tx.SigningHash()
+	sig := make([]byte, SignatureLength)
+	signed.R.FillBytes(sig[:32])
+	signed.S.FillBytes(sig[32:64])
+	sig[RecoveryIDOffset] = byte(v.Uint64())
+	if !VerifySignatureStrict(account.Address, hash, sig) {
+		t.Error("Transaction signature does not recover the sender")
+	}
+
+	// The hash is keccak256 of the raw encoding
+	txHash, err := signed.Hash()
+	if err != nil || hex.EncodeToString(txHash) != hex.EncodeToString(keccak256(raw)) {
+		t.Errorf("Hash = %x, %v", txHash, err)
+	}
+}
+
+func TestLegacyTxErrors(t *testing.T) {
+	wallet, account := newTestWallet(t)
+
+	unsigned := &Transaction{Gas: 21000, To: &Address{}}
+	if _, err := unsigned.MarshalBinary(); err != ErrUnsignedTx {
+		t.Errorf("Expected ErrUnsignedTx, got %v", err)
+	}
+	if _, err := unsigned.Hash(); err != ErrUnsignedTx {
+		t.Errorf("Expected ErrUnsignedTx, got %v", err)
+	}
+
+	invalid := []*Transaction{
+		{Value: big.NewInt(-1)},
+		{GasPrice: big.NewInt(-1)},
+		{ChainID: big.NewInt(-1)},
+		{Type: 0x7f},
+	}
+	for _, tx := range invalid {
+		if _, err := wallet.SignTx(account.Address, tx); !errors.Is(err, ErrInvalidTransaction) {
+			t.Errorf("SignTx(%+v) error = %v, want ErrInvalidTransaction", tx, err)
+		}
+	}
+
+	if _, err := unsigned.WithSignature(make([]byte, 64)); !errors.Is(err, ErrInvalidSignatureLength) {
+		t.Errorf("Expected ErrInvalidSignatureLength, got %v", err)
+	}
+	badV := make([]byte, SignatureLength)
+	badV[RecoveryIDOffset] = 27
+	if _, err := unsigned.WithSignature(badV); !errors.Is(err, ErrInvalidRecoveryID) {
+		t.Errorf("Expected ErrInvalidRecoveryID, got %v", err)
+	}
+
+	if _, err := wallet.SignTx(Address{}, unsigned); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+
+	wallet.isLocked = true
+	if _, err := wallet.SignTx(account.Address, unsigned); !errors.Is(err, ErrWalletLocked) {
+		t.Errorf("Expected ErrWalletLocked, got %v", err)
+	}
+}
+
+func TestAccessListTxVector(t *testing.T) {
+	// The EIP-2930 transaction of the go-ethereum type tests
+	tx := &Transaction{
+		Type:     AccessListTxType,
+		ChainID:  big.NewInt(1),
+		Nonce:    3,
+		GasPrice: big.NewInt(1),
+		Gas:      25000,
+		To:       addressPtr("0xb94f5374fce5edbc8e2a8697c15331677e6ebf0b"),
+		Value:    big.NewInt(10),
+		Data:     []byte{0x55, 0x44},
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "49b486f0ec0a60dfbbca2d30cb07c9e8ffb2a2ff41f29a1ab6737475f6ff69f3" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	sig, _ := hex.DecodeString("c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660" +
+		"32f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d3752101")
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+	if signed.V.Int64() != 1 {
+		t.Errorf("V = %v, want y-parity 1", signed.V)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	want := "01f8630103018261a894b94f5374fce5edbc8e2a8697c15331677e6ebf0b0a825544c001" +
+		"a0c9519f4f2b30335884581971573fadf60c6204f59a911df35ee8a540456b2660" +
+		"a032f1e8e2c5dd761f9e4f88f41c8310aeaba26a8bfcdacfedfa12ec3862d37521"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Errorf("MarshalBinary = %s, want %s", got, want)
+	}
+
+	txHash, _ := signed.Hash()
+	if got := hex.EncodeToString(txHash); got != "d900408d8fec1ffdb3e360685f94400b2ef6e1211ac0f98abbaa140e1a73683a" {
+		t.Errorf("Hash = %s", got)
+	}
+
+	sender, err := signed.Sender()
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if want := HexToAddress("0x27cf7d8449c9da59189427619ba59f985cee9c0f"); sender != want {
+		t.Errorf("Sender = %s, want %s", sender.Hex(), want.Hex())
+	}
+}
+
+func TestDynamicFeeTxVector(t *testing.T) {
+	key := mustBigInt("0x4646464646464646464646464646464646464646464646464646464646464646")
+	tx := &Transaction{
+		Type:      DynamicFeeTxType,
+		ChainID:   big.NewInt(1),
+		Nonce:     0,
+		GasTipCap: big.NewInt(1500000000),
+		GasFeeCap: big.NewInt(30000000000),
+		Gas:       21000,
+		To:        addressPtr("0x3535353535353535353535353535353535353535"),
+		Value:     mustBigInt("1000000000000000000"),
+		AccessList: AccessList{{
+			Address:     HexToAddress("0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae"),
+			StorageKeys: [][HashLength]byte{{31: 0x03}, {31: 0x07}},
+		}},
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Fatalf("SigningHash failed: %v", err)
+	}
+	if got := hex.EncodeToString(hash); got != "6d96c50918b7221b216af9b314fb0d8188121d860341587a7d8a6b34682e53da" {
+		t.Errorf("SigningHash = %s", got)
+	}
+
+	sig, err := signHash(key, hash)
+	if err != nil {
+		t.Fatalf("signHash failed: %v", err)
+	}
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	want := "02f8cf01808459682f008506fc23ac00825208943535353535353535353535353535353535353535880de0b6b3a764000080" +
+		"f85bf85994de0b295669a9fd93d5f28d9ec85e40f4cb697baef842" +
+		"a00000000000000000000000000000000000000000000000000000000000000003" +
+		"a00000000000000000000000000000000000000000000000000000000000000007" +
+		"80" +
+		"a0eb17350b619dd2c4197b3d030fe708547ac68b895e2837aebcfa09b9826f91f9" +
+		"a04ca23400ef66732790309d46f1717183eb2600f0cd0a98637e57754e7cb1e3b0"
+	if got := hex.EncodeToString(raw); got != want {
+		t.Errorf("MarshalBinary = %s, want %s", got, want)
+	}
+
+	txHash, _ := signed.Hash()
+	if got := hex.EncodeToString(txHash); got != "b8f43fe18d5af3a06efc51ecb7f662afb21e9632888a76acc165d2a66cc126c9" {
+		t.Errorf("Hash = %s", got)
+	}
+
+	// Decoding restores every field and the sender
+	decoded, err := ParseRawTransaction("0x" + want)
+	if err != nil {
+		t.Fatalf("ParseRawTransaction failed: %v", err)
+	}
+	if decoded.Type != DynamicFeeTxType || decoded.GasTipCap.Cmp(tx.GasTipCap) != 0 || decoded.GasFeeCap.Cmp(tx.GasFeeCap) != 0 ||
+		decoded.Gas != tx.Gas || *decoded.To != *tx.To || decoded.Value.Cmp(tx.Value) != 0 || decoded.GasPrice != nil {
+		t.Errorf("Decoded %+v", decoded)
+	}
+	if !reflect.DeepEqual(decoded.AccessList, tx.AccessList) {
+		t.Errorf("AccessList = %+v, want %+v", decoded.AccessList, tx.AccessList)
+	}
+	sender, err := decoded.Sender()
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if want := HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"); sender != want {
+		t.Errorf("Sender = %s, want %s", sender.Hex(), want.Hex())
+	}
+}
+
+func TestDecodeLegacyTx(t *testing.T) {
+	// The signed example from the EIP-155 specification
+	eip155 := "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025" +
+		"a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276" +
+		"a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	tx, err := ParseRawTransaction(eip155)
+	if err != nil {
+		t.Fatalf("ParseRawTransaction failed: %v", err)
+	}
+	if tx.Type != LegacyTxType || tx.ChainID.Int64() != 1 || tx.Nonce != 9 || tx.Gas != 21000 {
+		t.Errorf("Decoded %+v", tx)
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		t.Fatalf("Sender failed: %v", err)
+	}
+	if want := HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"); sender != want {
+		t.Errorf("Sender = %s, want %s", sender.Hex(), want.Hex())
+	}
+
+	// A legacy transaction must not carry a 0x00 type prefix
+	if _, err := ParseRawTransaction("00" + eip155); !errors.Is(err, ErrInvalidTransaction) {
+		t.Errorf("Expected ErrInvalidTransaction for a 0x00 prefix, got %v", err)
+	}
+
+	// Homestead transactions have no chain ID; decoding round trips
+	wallet, account := newTestWallet(t)
+	for _, unsigned := range []*Transaction{
+		{Gas: 21000, To: &Address{}, Value: big.NewInt(1)},
+		{Type: AccessListTxType, ChainID: big.NewInt(5), Gas: 60000, Data: []byte{0x60}},
+		{Type: DynamicFeeTxType, ChainID: big.NewInt(10), GasFeeCap: big.NewInt(7), Gas: 21000, To: &Address{}},
+	} {
+		raw, err := wallet.SignTx(account.Address, unsigned)
+		if err != nil {
+			t.Fatalf("SignTx failed: %v", err)
+		}
+		decoded, err := ParseRawTransaction(raw)
+		if err != nil {
+			t.Fatalf("ParseRawTransaction(%s) failed: %v", raw, err)
+		}
+		if unsigned.Type == LegacyTxType && decoded.ChainID != nil {
+			t.Errorf("Homestead transaction decoded with chain ID %v", decoded.ChainID)
+		}
+		if (decoded.To == nil) != (unsigned.To == nil) {
+			t.Errorf("Recipient = %v, want %v", decoded.To, unsigned.To)
+		}
+		again, _ := decoded.MarshalBinary()
+		if "0x"+hex.EncodeToString(again) != raw {
+			t.Errorf("Re-encoding = %x, want %s", again, raw)
+		}
+		if sender, err := decoded.Sender(); err != nil || sender != account.Address {
+			t.Errorf("Sender = %s, %v; want %s", sender.Hex(), err, account.Address.Hex())
+		}
+	}
+}
+
+func TestTypedTxErrors(t *testing.T) {
+	invalid := []*Transaction{
+		{Type: AccessListTxType},
+		{Type: DynamicFeeTxType},
+		{AccessList: AccessList{}},
+		{GasFeeCap: big.NewInt(1)},
+		{Type: AccessListTxType, ChainID: big.NewInt(1), GasTipCap: big.NewInt(1)},
+		{Type: DynamicFeeTxType, ChainID: big.NewInt(1), GasPrice: big.NewInt(1)},
+		{Type: DynamicFeeTxType, ChainID: big.NewInt(1), GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(1)},
+		{Type: DynamicFeeTxType, ChainID: big.NewInt(1), GasTipCap: big.NewInt(-1)},
+	}
+	for _, tx := range invalid {
+		if _, err := tx.SigningHash(); !errors.Is(err, ErrInvalidTransaction) {
+			t.Errorf("SigningHash(%+v) error = %v, want ErrInvalidTransaction", tx, err)
+		}
+	}
+
+	// A valid signed dynamic fee transaction to corrupt
+	valid := "02f8cf01808459682f008506fc23ac00825208943535353535353535353535353535353535353535880de0b6b3a764000080" +
+		"f85bf85994de0b295669a9fd93d5f28d9ec85e40f4cb697baef842" +
+		"a00000000000000000000000000000000000000000000000000000000000000003" +
+		"a00000000000000000000000000000000000000000000000000000000000000007" +
+		"80" +
+		"a0eb17350b619dd2c4197b3d030fe708547ac68b895e2837aebcfa09b9826f91f9" +
+		"a04ca23400ef66732790309d46f1717183eb2600f0cd0a98637e57754e7cb1e3b0"
+	if _, err := ParseRawTransaction(valid); err != nil {
+		t.Fatalf("ParseRawTransaction failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+		err  error
+	}{
+		{"empty", "", ErrInvalidTransaction},
+		{"not hex", "0xzz", ErrInvalidTransaction},
+		{"string envelope", "80", ErrInvalidTransaction},
+		{"unknown type", "7f" + valid[2:], ErrInvalidTransaction},
+		{"trailing data", valid + "00", ErrInvalidTransaction},
+		{"type 1 fields", "01" + valid[2:], ErrInvalidTransaction},
+		{"too few fields", "02c3010203", ErrInvalidTransaction},
+		{"non-canonical nonce", "02f8d001" + "8100" + valid[10:], ErrInvalidTransaction},
+		{"y-parity 2", strings.Replace(valid, "0780a0eb", "0702a0eb", 1), ErrInvalidRecoveryID},
+		{"short recipient", "f83c" + "808080" + "93" + strings.Repeat("35", 19) + "8080" + "1b" + "a0" + strings.Repeat("11", 32) + "01", ErrInvalidTransaction},
+		{"legacy V 29", "f83d" + "808080" + "94" + strings.Repeat("35", 20) + "8080" + "1d" + "a0" + strings.Repeat("11", 32) + "01", ErrInvalidRecoveryID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRawTransaction(tt.raw); !errors.Is(err, tt.err) {
+				t.Errorf("ParseRawTransaction error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	unsigned := &Transaction{Type: DynamicFeeTxType, ChainID: big.NewInt(1)}
+	if _, err := unsigned.Sender(); err != ErrUnsignedTx {
+		t.Errorf("Expected ErrUnsignedTx, got %v", err)
+	}
+	highS := &Transaction{
+		Type: DynamicFeeTxType, ChainID: big.NewInt(1),
+		V: big.NewInt(0), R: big.NewInt(1), S: new(big.Int).Sub(secp256k1N, big.NewInt(1)),
+	}
+	if _, err := highS.Sender(); !errors.Is(err, ErrHighS) {
+		t.Errorf("Expected ErrHighS, got %v", err)
+	}
+}
@@ -0,0 +1,504 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// domainTypeName is the struct type describing the EIP-712 signing domain
+const domainTypeName = "EIP712Domain"
+
+// ErrInvalidTypedData is returned for malformed EIP-712 documents and values
+var ErrInvalidTypedData = errors.New("invalid typed data")
+
+// TypedDataField is a named member of an EIP-712 struct type
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is an EIP-712 typed structured data document in the JSON form
+// accepted by eth_signTypedData_v4
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// domainFields lists the standard EIP712Domain members in canonical order,
+// used when a document omits the EIP712Domain type
+var domainFields = []TypedDataField{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+// ParseTypedData decodes and validates an eth_signTypedData_v4 JSON document.
+// Numbers are kept exact so that uint256 values survive decoding. When the
+// EIP712Domain type is missing it is inferred from the domain members present.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var td TypedData
+	if err := decoder.Decode(&td); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+	}
+
+	if td.Types == nil {
+		td.Types = make(map[string][]TypedDataField)
+	}
+	if _, exists := td.Types[domainTypeName]; !exists {
+		var fields []TypedDataField
+		for _, field := range domainFields {
+			if _, present := td.Domain[field.Name]; present {
+				fields = append(fields, field)
+			}
+		}
+		td.Types[domainTypeName] = fields
+	}
+
+	if err := td.validate(); err != nil {
+		return nil, err
+	}
+	return &td, nil
+}
+
+// validate checks that every field refers to a known type and that the
+// primary type is defined
+func (td *TypedData) validate() error {
+	if td.PrimaryType == "" {
+		return fmt.Errorf("%w: missing primaryType", ErrInvalidTypedData)
+	}
+	if _, exists := td.Types[td.PrimaryType]; !exists {
+		return fmt.Errorf("%w: primaryType %q is not defined", ErrInvalidTypedData, td.PrimaryType)
+	}
+
+	for name, fields := range td.Types {
+		if name == "" || strings.ContainsAny(name, "[](), ") {
+			return fmt.Errorf("%w: invalid type name %q", ErrInvalidTypedData, name)
+		}
+		if isAtomicType(name) || name == "string" || name == "bytes" {
+			return fmt.Errorf("%w: type %q shadows a primitive type", ErrInvalidTypedData, name)
+		}
+
+		seen := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			if field.Name == "" || seen[field.Name] {
+				return fmt.Errorf("%w: type %s has an empty or duplicate field %q", ErrInvalidTypedData, name, field.Name)
+			}
+			seen[field.Name] = true
+
+			base, _, err := splitArrayType(field.Type)
+			if err != nil {
+				return fmt.Errorf("%w: %s.%s: %v", ErrInvalidTypedData, name, field.Name, err)
+			}
+			if _, isStruct := td.Types[base]; !isStruct && !isAtomicType(base) && base != "string" && base != "bytes" {
+				return fmt.Errorf("%w: %s.%s has unknown type %q", ErrInvalidTypedData, name, field.Name, field.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// splitArrayType strips the outermost array suffix from a type, returning
+// the element type and the fixed length, or -1 for a dynamic array. Types
+// that are not arrays are returned unchanged with length 0.
+func splitArrayType(typ string) (string, int, error) {
+	if !strings.HasSuffix(typ, "]") {
+		return typ, 0, nil
+	}
+	open := strings.LastIndexByte(typ, '[')
+	if open <= 0 {
+		return "", 0, fmt.Errorf("malformed array type %q", typ)
+	}
+
+	elem, size := typ[:open], typ[open+1:len(typ)-1]
+	if size == "" {
+		return elem, -1, nil
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 || strconv.Itoa(n) != size {
+		return "", 0, fmt.Errorf("malformed array length in %q", typ)
+	}
+	return elem, n, nil
+}
+
+// baseType strips all array suffixes from a type
+func baseType(typ string) string {
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		return typ[:i]
+	}
+	return typ
+}
+
+// isAtomicType reports whether typ is an EIP-712 atomic type: bool, address,
+// bytes1 to bytes32, or uint8 to uint256 and int8 to int256 in steps of 8
+func isAtomicType(typ string) bool {
+	switch {
+	case typ == "bool" || typ == "address":
+		return true
+	case strings.HasPrefix(typ, "bytes"):
+		n, err := strconv.Atoi(typ[len("bytes"):])
+		return err == nil && n >= 1 && n <= 32 && strconv.Itoa(n) == typ[len("bytes"):]
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		bits, err := integerBits(typ)
+		return err == nil && bits > 0
+	}
+	return false
+}
+
+// integerBits returns the width of a uintN or intN type
+func integerBits(typ string) (int, error) {
+	size := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int")
+	n, err := strconv.Atoi(size)
+	if err != nil || n < 8 || n > 256 || n%8 != 0 || strconv.Itoa(n) != size {
+		return 0, fmt.Errorf("invalid integer type %q", typ)
+	}
+	return n, nil
+}
+
+// dependencies collects the struct types referenced by primary, directly or
+// transitively, including primary itself
+func (td *TypedData) dependencies(primary string, found map[string]bool) {
+	if found[primary] {
+		return
+	}
+	if _, exists := td.Types[primary]; !exists {
+		return
+	}
+	found[primary] = true
+	for _, field := range td.Types[primary] {
+		td.dependencies(baseType(field.Type), found)
+	}
+}
+
+// EncodeType returns the EIP-712 type encoding of a struct type: its own
+// signature followed by the signatures of all referenced struct types sorted
+// by name, e.g. "Mail(Person from,Person to,string contents)Person(string name,address wallet)"
+func (td *TypedData) EncodeType(primary string) (string, error) {
+	if _, exists := td.Types[primary]; !exists {
+		return "", fmt.Errorf("%w: type %q is not defined", ErrInvalidTypedData, primary)
+	}
+
+	found := make(map[string]bool)
+	td.dependencies(primary, found)
+	delete(found, primary)
+
+	deps := make([]string, 0, len(found))
+	for dep := range found {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+
+	var b strings.Builder
+	for _, name := range append([]string{primary}, deps...) {
+		b.WriteString(name)
+		b.WriteByte('(')
+		for i, field := range td.Types[name] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(field.Type)
+			b.WriteByte(' ')
+			b.WriteString(field.Name)
+		}
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// TypeHash returns keccak256(EncodeType(primary))
+func (td *TypedData) TypeHash(primary string) ([]byte, error) {
+	encoded, err := td.EncodeType(primary)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte(encoded)), nil
+}
+
+// EncodeData returns the EIP-712 encoding of a struct value: its type hash
+// followed by one 32-byte word per field. Members of data that the type does
+// not define are rejected, as they would not be covered by the signature.
+func (td *TypedData) EncodeData(primary string, data map[string]interface{}) ([]byte, error) {
+	typeHash, err := td.TypeHash(primary)
+	if err != nil {
+		return nil, err
+	}
+
+	defined := make(map[string]bool, len(td.Types[primary]))
+	for _, field := range td.Types[primary] {
+		defined[field.Name] = true
+	}
+	var unknown []string
+	for name := range data {
+		if !defined[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: %s has undefined member %q", ErrInvalidTypedData, primary, unknown[0])
+	}
+
+	encoded := make([]byte, 0, 32*(len(td.Types[primary])+1))
+	encoded = append(encoded, typeHash...)
+
+	for _, field := range td.Types[primary] {
+		value, present := data[field.Name]
+		if !present {
+			value = nil
+		}
+		word, err := td.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", primary, field.Name, err)
+		}
+		encoded = append(encoded, word...)
+	}
+
+	return encoded, nil
+}
+
+// HashStruct returns keccak256(EncodeData(primary, data))
+func (td *TypedData) HashStruct(primary string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := td.EncodeData(primary, data)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256(encoded), nil
+}
+
+// DomainSeparator returns hashStruct(EIP712Domain, domain)
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	return td.HashStruct(domainTypeName, td.Domain)
+}
+
+// SigningHash returns the digest signed by eth_signTypedData_v4:
+// keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func (td *TypedData) SigningHash() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, fmt.Errorf("domain: %w", err)
+	}
+
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, fmt.Errorf("message: %w", err)
+	}
+
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// encodeValue encodes one field value as a 32-byte word. Struct values are
+// replaced by their hashStruct, dynamic values and arrays by the hash of
+// their contents, and a null struct encodes as zero.
+func (td *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	elem, size, err := splitArrayType(typ)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+	}
+
+	// Arrays hash the concatenated encodings of their elements
+	if size != 0 {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected array for %s, got %T", ErrInvalidTypedData, typ, value)
+		}
+		if size > 0 && len(items) != size {
+			return nil, fmt.Errorf("%w: %s has %d elements", ErrInvalidTypedData, typ, len(items))
+		}
+
+		encoded := make([]byte, 0, 32*len(items))
+		for i, item := range items {
+			word, err := td.encodeValue(elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			encoded = append(encoded, word...)
+		}
+		return keccak256(encoded), nil
+	}
+
+	if _, isStruct := td.Types[typ]; isStruct {
+		if value == nil {
+			return make([]byte, 32), nil
+		}
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected object for %s, got %T", ErrInvalidTypedData, typ, value)
+		}
+		return td.HashStruct(typ, fields)
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("%w: missing value of type %s", ErrInvalidTypedData, typ)
+	}
+
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected string, got %T", ErrInvalidTypedData, value)
+		}
+		return keccak256([]byte(s)), nil
+
+	case "bytes":
+		b, err := typedDataBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return keccak256(b), nil
+	}
+
+	return encodeAtomic(typ, value)
+}
+
+// encodeAtomic encodes a bool, address, bytesN, uintN or intN value
+func encodeAtomic(typ string, value interface{}) ([]byte, error) {
+	word := make([]byte, 32)
+
+	switch {
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected bool, got %T", ErrInvalidTypedData, value)
+		}
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+
+	case typ == "address":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected address string, got %T", ErrInvalidTypedData, value)
+		}
+		address, err := ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		copy(word[12:], address[:])
+		return word, nil
+
+	case strings.HasPrefix(typ, "bytes"):
+		size, _ := strconv.Atoi(typ[len("bytes"):])
+		b, err := typedDataBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != size {
+			return nil, fmt.Errorf("%w: %s value has %d bytes", ErrInvalidTypedData, typ, len(b))
+		}
+		copy(word, b)
+		return word, nil
+	}
+
+	bits, err := integerBits(typ)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+	}
+	n, err := typedDataInteger(value)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := !strings.HasPrefix(typ, "u")
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		limit.Rsh(limit, 1)
+	}
+	min := new(big.Int)
+	if signed {
+		min.Neg(limit)
+	}
+	if n.Cmp(min) < 0 || n.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("%w: %s out of range for %s", ErrInvalidTypedData, n, typ)
+	}
+
+	// Negative values use 256-bit two's complement
+	if n.Sign() < 0 {
+		n.Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	n.FillBytes(word)
+	return word, nil
+}
+
+// typedDataInteger parses a JSON number, decimal string or 0x-prefixed hex
+// string as an integer
+func typedDataInteger(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("%w: %v is not an integer", ErrInvalidTypedData, v)
+		}
+		return big.NewInt(int64(v)), nil
+	default:
+		return nil, fmt.Errorf("%w: expected integer, got %T", ErrInvalidTypedData, value)
+	}
+
+	n := new(big.Int)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+
+	ok := false
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		_, ok = n.SetString(digits[2:], 16)
+	} else if digits != "" && strings.Trim(digits, "0123456789") == "" {
+		_, ok = n.SetString(digits, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not an integer", ErrInvalidTypedData, s)
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// typedDataBytes decodes a 0x-prefixed hex string
+func typedDataBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected hex string, got %T", ErrInvalidTypedData, value)
+	}
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("%w: hex value %q lacks 0x prefix", ErrInvalidTypedData, s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTypedData, err)
+	}
+	return b, nil
+}
+
+// SignTypedData signs an EIP-712 document with the key of a derived account
+// and returns a 65-byte [R || S || V] signature with V in {27, 28}, the form
+// returned by eth_signTypedData_v4
+func (w *SimpleWallet) SignTypedData(address Address, td *TypedData) ([]byte, error) {
+	hash, err := td.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := w.SignHash(address, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %w", err)
+	}
+	sig[RecoveryIDOffset] += 27
+	return sig, nil
+}
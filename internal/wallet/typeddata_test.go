@@ -0,0 +1,333 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func loadTypedData(t *testing.T, name string) *TypedData {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", name, err)
+	}
+	td, err := ParseTypedData(data)
+	if err != nil {
+		t.Fatalf("ParseTypedData(%s) failed: %v", name, err)
+	}
+	return td
+}
+
+func TestTypedDataVectors(t *testing.T) {
+	// The EIP-712 specification example and the eth_signTypedData_v4 array
+	// example, both signed with keccak256("cow")
+	tests := []struct {
+		file            string
+		encodeType      string
+		typeHash        string
+		domainSeparator string
+		hashStruct      string
+		signingHash     string
+		signature       string
+	}{
+		{
+			file:            "mail.json",
+			encodeType:      "Mail(Person from,Person to,string contents)Person(string name,address wallet)",
+			typeHash:        "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2",
+			domainSeparator: "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			hashStruct:      "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+			signingHash:     "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+			signature: "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+				"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c",
+		},
+		{
+			file:            "mail_arrays.json",
+			encodeType:      "Mail(Person from,Person[] to,string contents)Person(string name,address[] wallets)",
+			typeHash:        "4bd8a9a2b93427bb184aca81e24beb30ffa3c747e2a33d4225ec08bf12e2e753",
+			domainSeparator: "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			hashStruct:      "eb4221181ff3f1a83ea7313993ca9218496e424604ba9492bb4052c03d5c3df8",
+			signingHash:     "a85c2e2b118698e88db68a8105b794a8cc7cec074e89ef991cb4f5f533819cc2",
+			signature: "65cbd956f2fae28a601bebc9b906cea0191744bd4c4247bcd27cd08f8eb6b71c" +
+				"78efdf7a31dc9abee78f492292721f362d296cf86b4538e07b51303b67f74906" + "1b",
+		},
+	}
+
+	cow := new(big.Int).SetBytes(keccak256([]byte("cow")))
+	signer := HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			td := loadTypedData(t, tt.file)
+
+			encoded, err := td.EncodeType(td.PrimaryType)
+			if err != nil || encoded != tt.encodeType {
+				t.Errorf("EncodeType = %q, %v; want %q", encoded, err, tt.encodeType)
+			}
+
+			check := func(name string, got []byte, err error, want string) {
+				t.Helper()
+				if err != nil {
+					t.Errorf("%s failed: %v", name, err)
+				} else if hex.EncodeToString(got) != want {
+					t.Errorf("%s = %x, want %s", name, got, want)
+				}
+			}
+
+			typeHash, err := td.TypeHash(td.PrimaryType)
+			check("TypeHash", typeHash, err, tt.typeHash)
+			domainSeparator, err := td.DomainSeparator()
+			check("DomainSeparator", domainSeparator, err, tt.domainSeparator)
+			hashStruct, err := td.HashStruct(td.PrimaryType, td.Message)
+			check("HashStruct", hashStruct, err, tt.hashStruct)
+			signingHash, err := td.SigningHash()
+			check("SigningHash", signingHash, err, tt.signingHash)
+
+			sig, err := signHash(cow, signingHash)
+			if err != nil {
+				t.Fatalf("signHash failed: %v", err)
+			}
+			sig[RecoveryIDOffset] += 27
+			if got := hex.EncodeToString(sig); got != tt.signature {
+				t.Errorf("Signature = %s, want %s", got, tt.signature)
+			}
+			if !VerifySignature(signer, signingHash, sig) {
+				t.Error("Signature does not verify against the signer")
+			}
+		})
+	}
+}
+
+func TestTypedDataEncodeTypeDependencies(t *testing.T) {
+	td := &TypedData{
+		Types: map[string][]TypedDataField{
+			"Order":  {{"maker", "Party"}, {"legs", "Leg[2]"}, {"note", "string"}},
+			"Party":  {{"account", "address"}, {"asset", "Asset"}},
+			"Leg":    {{"asset", "Asset"}, {"amount", "uint256"}},
+			"Asset":  {{"token", "address"}, {"id", "uint256"}},
+			"Unused": {{"x", "bool"}},
+		},
+		PrimaryType: "Order",
+	}
+
+	want := "Order(Party maker,Leg[2] legs,string note)" +
+		"Asset(address token,uint256 id)" +
+		"Leg(Asset asset,uint256 amount)" +
+		"Party(address account,Asset asset)"
+	if got, err := td.EncodeType("Order"); err != nil || got != want {
+		t.Errorf("EncodeType = %q, %v; want %q", got, err, want)
+	}
+
+	// Self-referencing types terminate and appear once
+	td.Types["Node"] = []TypedDataField{{"value", "uint8"}, {"children", "Node[]"}}
+	if got, err := td.EncodeType("Node"); err != nil || got != "Node(uint8 value,Node[] children)" {
+		t.Errorf("EncodeType(Node) = %q, %v", got, err)
+	}
+}
+
+func TestTypedDataAtomicEncoding(t *testing.T) {
+	word := func(h string) string {
+		for len(h) < 64 {
+			h = "0" + h
+		}
+		return h
+	}
+	ones := "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+	tests := []struct {
+		typ   string
+		value interface{}
+		want  string
+	}{
+		{"bool", true, word("1")},
+		{"bool", false, word("0")},
+		{"uint8", "255", word("ff")},
+		{"uint256", "0x10", word("10")},
+		{"uint256", 1.0, word("1")},
+		{"int8", "-1", ones},
+		{"int256", "-2", ones[:63] + "e"},
+		{"int16", "-32768", ones[:60] + "8000"},
+		{"address", "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", word("cd2a3d9f938e13cd947ec05abc7fe734df8dd826")},
+		{"bytes1", "0xab", "ab" + word("")[2:]},
+		{"bytes4", "0xdeadbeef", "deadbeef" + word("")[8:]},
+		{"bytes32", "0x" + ones, ones},
+	}
+
+	for _, tt := range tests {
+		got, err := encodeAtomic(tt.typ, tt.value)
+		if err != nil {
+			t.Errorf("encodeAtomic(%s, %v) failed: %v", tt.typ, tt.value, err)
+		} else if hex.EncodeToString(got) != tt.want {
+			t.Errorf("encodeAtomic(%s, %v) = %x, want %s", tt.typ, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestTypedDataDynamicEncoding(t *testing.T) {
+	td := &TypedData{Types: map[string][]TypedDataField{}}
+
+	got, err := td.encodeValue("string", "Hello, Bob!")
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(keccak256([]byte("Hello, Bob!"))) {
+		t.Errorf("string encoding = %x, %v", got, err)
+	}
+
+	got, err = td.encodeValue("bytes", "0x0102")
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(keccak256([]byte{1, 2})) {
+		t.Errorf("bytes encoding = %x, %v", got, err)
+	}
+
+	// Arrays hash the concatenation of their encoded elements
+	one, _ := encodeAtomic("uint8", "1")
+	two, _ := encodeAtomic("uint8", "2")
+	got, err = td.encodeValue("uint8[2]", []interface{}{"1", "2"})
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(keccak256(one, two)) {
+		t.Errorf("uint8[2] encoding = %x, %v", got, err)
+	}
+
+	// Nested arrays hash each inner array first
+	got, err = td.encodeValue("uint8[][]", []interface{}{[]interface{}{"1"}, []interface{}{}})
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(keccak256(keccak256(one), keccak256())) {
+		t.Errorf("uint8[][] encoding = %x, %v", got, err)
+	}
+}
+
+func TestTypedDataErrors(t *testing.T) {
+	td := &TypedData{Types: map[string][]TypedDataField{"Person": {{"name", "string"}}}}
+
+	values := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"bool", "true"},
+		{"uint8", "256"},
+		{"uint8", "-1"},
+		{"int8", "128"},
+		{"int8", "-129"},
+		{"uint256", "1.5"},
+		{"uint256", 1.5},
+		{"uint256", "12abc"},
+		{"address", "0x1234"},
+		{"address", 42.0},
+		{"bytes4", "0xdeadbe"},
+		{"bytes4", "deadbeef"},
+		{"bytes", "0xzz"},
+		{"string", 7.0},
+		{"string", nil},
+		{"uint8[2]", []interface{}{"1"}},
+		{"uint8[]", "1"},
+		{"Person", "Bob"},
+		{"Person", map[string]interface{}{"name": "Bob", "wallet": "0x0"}},
+	}
+	for _, tt := range values {
+		if _, err := td.encodeValue(tt.typ, tt.value); !errors.Is(err, ErrInvalidTypedData) && !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("encodeValue(%s, %v) error = %v, want ErrInvalidTypedData", tt.typ, tt.value, err)
+		}
+	}
+
+	// A null struct value encodes as zero
+	if got, err := td.encodeValue("Person", nil); err != nil || hex.EncodeToString(got) != hex.EncodeToString(make([]byte, 32)) {
+		t.Errorf("null struct encoding = %x, %v", got, err)
+	}
+
+	documents := []string{
+		`not json`,
+		`{"types":{"Mail":[]},"domain":{},"message":{}}`,
+		`{"types":{"Mail":[]},"primaryType":"Letter","domain":{},"message":{}}`,
+		`{"types":{"Mail":[{"name":"to","type":"Person"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"Mail":[{"name":"n","type":"uint7"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"Mail":[{"name":"n","type":"bytes33"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"Mail":[{"name":"n","type":"uint8[0]"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"Mail":[{"name":"n","type":"bool"},{"name":"n","type":"bool"}]},"primaryType":"Mail","domain":{},"message":{}}`,
+		`{"types":{"uint256":[],"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`,
+	}
+	for _, doc := range documents {
+		if _, err := ParseTypedData([]byte(doc)); !errors.Is(err, ErrInvalidTypedData) {
+			t.Errorf("ParseTypedData(%s) error = %v, want ErrInvalidTypedData", doc, err)
+		}
+	}
+
+	// A message member missing from the type would be shown but not signed
+	extra, err := ParseTypedData([]byte(`{"types":{"Mail":[{"name":"note","type":"string"}]},"primaryType":"Mail",` +
+		`"domain":{"name":"Ether Mail"},"message":{"note":"hi","amount":"1000000"}}`))
+	if err != nil {
+		t.Fatalf("ParseTypedData failed: %v", err)
+	}
+	if _, err := extra.SigningHash(); !errors.Is(err, ErrInvalidTypedData) {
+		t.Errorf("SigningHash with an undefined member error = %v, want ErrInvalidTypedData", err)
+	}
+}
+
+func TestTypedDataInferredDomain(t *testing.T) {
+	explicit := loadTypedData(t, "mail.json")
+
+	// Without an EIP712Domain type the members present in domain are used
+	// in their canonical order
+	data, err := os.ReadFile("testdata/mail.json")
+	if err != nil {
+		t.Fatalf("Failed to read mail.json: %v", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	delete(doc["types"].(map[string]interface{}), domainTypeName)
+	data, _ = json.Marshal(doc)
+
+	inferred, err := ParseTypedData(data)
+	if err != nil {
+		t.Fatalf("ParseTypedData failed: %v", err)
+	}
+
+	want, _ := explicit.SigningHash()
+	got, err := inferred.SigningHash()
+	if err != nil || hex.EncodeToString(got) != hex.EncodeToString(want) {
+		t.Errorf("Inferred domain signing hash = %x, %v; want %x", got, err, want)
+	}
+
+	// Large integers survive decoding exactly
+	maxUint := `{"types":{"T":[{"name":"v","type":"uint256"}]},"primaryType":"T","domain":{"chainId":1},` +
+		`"message":{"v":115792089237316195423570985008687907853269984665640564039457584007913129639935}}`
+	td, err := ParseTypedData([]byte(maxUint))
+	if err != nil {
+		t.Fatalf("ParseTypedData failed: %v", err)
+	}
+	encoded, err := td.EncodeData("T", td.Message)
+	if err != nil || hex.EncodeToString(encoded[32:]) != "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" {
+		t.Errorf("uint256 max encoding = %x, %v", encoded, err)
+	}
+}
+
+func TestWalletSignTypedData(t *testing.T) {
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+
+	td := loadTypedData(t, "mail_arrays.json")
+	sig, err := wallet.SignTypedData(account.Address, td)
+	if err != nil {
+		t.Fatalf("SignTypedData failed: %v", err)
+	}
+	if v := sig[RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Errorf("V = %d, want 27 or 28", v)
+	}
+
+	hash, _ := td.SigningHash()
+	if !VerifySignatureStrict(account.Address, hash, sig) {
+		t.Error("Typed data signature does not verify")
+	}
+
+	td.Message["contents"] = 42.0
+	if _, err := wallet.SignTypedData(account.Address, td); !errors.Is(err, ErrInvalidTypedData) {
+		t.Errorf("Expected ErrInvalidTypedData, got %v", err)
+	}
+}
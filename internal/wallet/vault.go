@@ -0,0 +1,393 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Vault file constants
+const (
+	// VaultVersion is the vault file format version written by Save
+	VaultVersion = 1
+
+	vaultCipher = "aes-256-gcm"
+	// maxVaultSize bounds vault files read by OpenWallet
+	maxVaultSize = 4 << 20
+)
+
+// ErrInvalidVault is returned for vault files that are malformed or of an
+// unsupported version
+var ErrInvalidVault = errors.New("invalid wallet vault")
+
+// vaultKDFParams are the key derivation parameters of vaults written by Save
+var vaultKDFParams = StandardKeystoreParams
+
+// vaultFile is the on-disk vault layout. The header (everything but the
+// ciphertext) is readable without the passphrase and is authenticated as
+// AES-GCM additional data.
+type vaultFile struct {
+	Version   int         `json:"version"`
+	ID        string      `json:"id"`
+	Label     string      `json:"label,omitempty"`
+	Addresses []Address   `json:"addresses"`
+	Crypto    vaultCrypto `json:"crypto"`
+}
+
+// vaultCrypto describes how the vault payload is encrypted
+type vaultCrypto struct {
+	Cipher     string                 `json:"cipher"`
+	Nonce      keystoreHex            `json:"nonce"`
+	KDF        string                 `json:"kdf"`
+	KDFParams  map[string]interface{} `json:"kdfparams"`
+	CipherText keystoreHex            `json:"ciphertext,omitempty"`
+}
+
+// vaultPayload is the encrypted content of a vault
+type vaultPayload struct {
+	Mnemonic string         `json:"mnemonic,omitempty"`
+	Seed     keystoreHex    `json:"seed"`
+	Scheme   string         `json:"scheme"`
+	Accounts []vaultAccount `json:"accounts"`
+}
+
+// vaultAccount records a derived account so that OpenWallet can re-derive it
+type vaultAccount struct {
+	Path      string    `json:"path"`
+	Index     uint32    `json:"index"`
+	Label     string    `json:"label,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// VaultInfo is the unencrypted header of a vault file
+type VaultInfo struct {
+	ID        string
+	Label     string
+	Addresses []Address
+}
+
+// additionalData returns the authenticated header of the vault
+func (v *vaultFile) additionalData() ([]byte, error) {
+	header := *v
+	header.Crypto.CipherText = nil
+	return json.Marshal(header)
+}
+
+// ID returns the wallet's vault identifier, assigned by the first Save or
+// read by OpenWallet. It is empty for a wallet that was never saved.
+func (w *SimpleWallet) ID() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.id
+}
+
+// Label returns the wallet label stored in its vault
+func (w *SimpleWallet) Label() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.label
+}
+
+// SetLabel sets the wallet label stored in its vault
+func (w *SimpleWallet) SetLabel(label string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.label = label
+}
+
+// SetAccountLabel sets the label of a derived account
+func (w *SimpleWallet) SetAccountLabel(address Address, label string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	account, exists := w.accounts[address]
+	if !exists {
+		return ErrAccountNotFound
+	}
+	account.Label = label
+	return nil
+}
+
+// Save writes the wallet to an encrypted vault file at path, replacing any
+// existing file. The seed, mnemonic, derivation scheme and derived accounts
+// with their labels are encrypted with AES-256-GCM under a scrypt key from
+// passphrase. The wallet ID, label and account addresses are stored in the
+// clear so that vaults can be listed without unlocking them. It returns
+// ErrWalletLocked if the wallet is locked.
+func (w *SimpleWallet) Save(path, passphrase string) error {
+	return w.save(path, passphrase, true)
+}
+
+// SaveNew writes the wallet to a new vault file at path as Save does, but
+// fails with an error satisfying errors.Is(err, fs.ErrExist) if path already
+// exists, even when the file appears while the vault is being encrypted
+func (w *SimpleWallet) SaveNew(path, passphrase string) error {
+	return w.save(path, passphrase, false)
+}
+
+// save writes the vault file, replacing an existing one if replace is set
+func (w *SimpleWallet) save(path, passphrase string, replace bool) error {
+	vault, plaintext, err := w.vaultSnapshot()
+	if err != nil {
+		return err
+	}
+	defer secureClear(plaintext)
+
+	// Derive the key and write without w.mu: the vault KDF is deliberately
+	// slow and would hold up signing, Lock and the auto-lock timer
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	vault.Crypto = vaultCrypto{
+		Cipher: vaultCipher,
+		KDF:    KDFScrypt,
+		KDFParams: map[string]interface{}{
+			"n":     vaultKDFParams.ScryptN,
+			"r":     8,
+			"p":     vaultKDFParams.ScryptP,
+			"dklen": keystoreKeyLength,
+			"salt":  hex.EncodeToString(salt),
+		},
+	}
+
+	aead, err := vaultAEAD([]byte(passphrase), vault.Crypto.KDF, vault.Crypto.KDFParams)
+	if err != nil {
+		return err
+	}
+	vault.Crypto.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(vault.Crypto.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	ad, err := vault.additionalData()
+	if err != nil {
+		return err
+	}
+	vault.Crypto.CipherText = aead.Seal(nil, vault.Crypto.Nonce, plaintext, ad)
+
+	data, err := json.MarshalIndent(vault, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'), replace)
+}
+
+// vaultSnapshot returns the vault header and the JSON payload to encrypt,
+// assigning the wallet ID on first use. The caller must clear the payload.
+func (w *SimpleWallet) vaultSnapshot() (*vaultFile, []byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isLocked || w.seed == nil {
+		return nil, nil, ErrWalletLocked
+	}
+	if w.id == "" {
+		id, err := newUUID()
+		if err != nil {
+			return nil, nil, err
+		}
+		w.id = id
+	}
+
+	payload := vaultPayload{
+		Mnemonic: w.mnemonic,
+		Seed:     w.seed,
+		Scheme:   w.scheme.Name,
+	}
+	accounts := make([]*Account, 0, len(w.accounts))
+	for _, account := range w.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if !accounts[i].CreatedAt.Equal(accounts[j].CreatedAt) {
+			return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+		}
+		return accounts[i].Path < accounts[j].Path
+	})
+	addresses := make([]Address, len(accounts))
+	for i, account := range accounts {
+		addresses[i] = account.Address
+		payload.Accounts = append(payload.Accounts, vaultAccount{
+			Path:      account.Path,
+			Index:     account.Index,
+			Label:     account.Label,
+			CreatedAt: account.CreatedAt,
+		})
+	}
+
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &vaultFile{
+		Version:   VaultVersion,
+		ID:        w.id,
+		Label:     w.label,
+		Addresses: addresses,
+	}, plaintext, nil
+}
+
+// OpenWallet decrypts the vault file at path and restores the wallet with its
+// derived accounts and labels. It returns ErrInvalidPassphrase if the
+// passphrase is wrong or the file was modified. The derivation scheme comes
+// from the vault; config supplies the remaining options, and the vault
+// passphrase replaces config.LockPassphrase as the passphrase that unlocks the
+// wallet after Lock.
+func OpenWallet(path, passphrase string, config *WalletConfig) (*SimpleWallet, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	vault, err := readVault(path)
+	if err != nil {
+		return nil, err
+	}
+	if vault.Crypto.Cipher != vaultCipher {
+		return nil, fmt.Errorf("%w: unsupported cipher %q", ErrInvalidVault, vault.Crypto.Cipher)
+	}
+
+	aead, err := vaultAEAD([]byte(passphrase), vault.Crypto.KDF, vault.Crypto.KDFParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(vault.Crypto.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce must be %d bytes", ErrInvalidVault, aead.NonceSize())
+	}
+	ad, err := vault.additionalData()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, vault.Crypto.Nonce, vault.Crypto.CipherText, ad)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	defer secureClear(plaintext)
+
+	var payload vaultPayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVault, err)
+	}
+	defer secureClear(payload.Seed)
+
+	if len(payload.Seed) != SeedLength {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVault, ErrInvalidSeed)
+	}
+
+	walletConfig := *config
+	walletConfig.LockPassphrase = passphrase
+	walletConfig.Scheme = payload.Scheme
+	w, err := newWallet(payload.Mnemonic, payload.Seed, &walletConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVault, err)
+	}
+	w.id = vault.ID
+	w.label = vault.Label
+
+	for _, stored := range payload.Accounts {
+		path, err := ParseDerivationPath(stored.Path)
+		if err != nil {
+			w.Close()
+			return nil, fmt.Errorf("%w: %v", ErrInvalidVault, err)
+		}
+		account, err := w.deriveAccount(path, stored.Index)
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		account.Label = stored.Label
+		account.CreatedAt = stored.CreatedAt
+	}
+
+	return w, nil
+}
+
+// ReadVaultInfo reads the unencrypted header of the vault file at path
+// without decrypting it
+func ReadVaultInfo(path string) (*VaultInfo, error) {
+	vault, err := readVault(path)
+	if err != nil {
+		return nil, err
+	}
+	return &VaultInfo{ID: vault.ID, Label: vault.Label, Addresses: vault.Addresses}, nil
+}
+
+// readVault reads and parses a vault file, checking its version
+func readVault(path string) (*vaultFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var vault vaultFile
+	decoder := json.NewDecoder(io.LimitReader(f, maxVaultSize))
+	if err := decoder.Decode(&vault); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVault, err)
+	}
+	if vault.Version != VaultVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidVault, vault.Version)
+	}
+	if vault.ID == "" {
+		return nil, fmt.Errorf("%w: missing id", ErrInvalidVault)
+	}
+	return &vault, nil
+}
+
+// vaultAEAD returns the AES-256-GCM cipher keyed by the vault KDF
+func vaultAEAD(passphrase []byte, kdf string, params map[string]interface{}) (cipher.AEAD, error) {
+	key, err := keystoreKey(passphrase, kdf, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVault, err)
+	}
+	defer secureClear(key)
+
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, readable only by the owner, so that a failed write never leaves
+// a truncated file behind. Without replace the temporary file is hard-linked
+// into place, which fails if path exists.
+func writeFileAtomic(path string, data []byte, replace bool) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if !replace {
+		return os.Link(tmp.Name(), path)
+	}
+	return os.Rename(tmp.Name(), path)
+}
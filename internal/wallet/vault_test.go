@@ -0,0 +1,221 @@
+package wallet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useFastVaultKDF makes Save use cheap scrypt parameters for the test
+func useFastVaultKDF(t *testing.T) {
+	saved := vaultKDFParams
+	vaultKDFParams = testKeystoreParams
+	t.Cleanup(func() { vaultKDFParams = saved })
+}
+
+func TestVaultRoundTrip(t *testing.T) {
+	useFastVaultKDF(t)
+	path := filepath.Join(t.TempDir(), "wallet.json")
+
+	config := DefaultConfig()
+	config.Passphrase = "TREZOR"
+	config.Scheme = SchemeLedgerLive
+	wallet, err := NewFromMnemonic(demoMnemonic, config)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+	wallet.SetLabel("treasury")
+
+	first, err := wallet.Derive(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	second, err := wallet.DeriveAtPathString("m/44'/60'/0'/0/7")
+	if err != nil {
+		t.Fatalf("Failed to derive account: %v", err)
+	}
+	if err := wallet.SetAccountLabel(second.Address, "payroll"); err != nil {
+		t.Fatalf("SetAccountLabel failed: %v", err)
+	}
+	if err := wallet.SaveNew(path, "vault secret"); err != nil {
+		t.Fatalf("SaveNew failed: %v", err)
+	}
+	if wallet.ID() == "" {
+		t.Error("Save did not assign an ID")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Vault not written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Vault mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "volcano") || strings.Contains(string(data), "payroll") {
+		t.Error("Vault contains plaintext secrets")
+	}
+
+	header, err := ReadVaultInfo(path)
+	if err != nil {
+		t.Fatalf("ReadVaultInfo failed: %v", err)
+	}
+	if header.ID != wallet.ID() || header.Label != "treasury" || len(header.Addresses) != 2 {
+		t.Errorf("Vault header = %+v", header)
+	}
+
+	if _, err := OpenWallet(path, "wrong", nil); err != ErrInvalidPassphrase {
+		t.Errorf("OpenWallet error = %v, want ErrInvalidPassphrase", err)
+	}
+
+	opened, err := OpenWallet(path, "vault secret", nil)
+	if err != nil {
+		t.Fatalf("OpenWallet failed: %v", err)
+	}
+	defer opened.Close()
+
+	if opened.ID() != wallet.ID() || opened.Label() != "treasury" || opened.Scheme().Name != SchemeLedgerLive {
+		t.Errorf("Opened wallet is %s %q %s", opened.ID(), opened.Label(), opened.Scheme().Name)
+	}
+	mnemonic, err := opened.GetMnemonic()
+	if err != nil || mnemonic != demoMnemonic {
+		t.Errorf("Opened mnemonic = %q, %v", mnemonic, err)
+	}
+	for _, want := range []*Account{first, second} {
+		got := findAccount(opened, want.Address)
+		if got == nil {
+			t.Fatalf("Opened wallet lacks %s", want.Address.Hex())
+		}
+		if got.Path != want.Path || got.Index != want.Index || got.Label != want.Label || !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("Opened account = %+v, want %+v", got, want)
+		}
+		if got.PrivateKey.D.Cmp(want.PrivateKey.D) != 0 {
+			t.Errorf("Opened account %s has a different key", got.Address.Hex())
+		}
+	}
+
+	// Re-deriving keeps the label, and saving again keeps the ID
+	if account, _ := opened.DeriveAtPathString(second.Path); account.Label != "payroll" {
+		t.Errorf("Re-derived label = %q, want payroll", account.Label)
+	}
+	if err := opened.Save(path, "new secret"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if header, _ := ReadVaultInfo(path); header.ID != wallet.ID() {
+		t.Errorf("Saved ID = %s, want %s", header.ID, wallet.ID())
+	}
+
+	// The vault passphrase unlocks an opened wallet
+	if err := opened.Lock(); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if err := opened.Save(path, "vault secret"); err != ErrWalletLocked {
+		t.Errorf("Locked Save error = %v, want ErrWalletLocked", err)
+	}
+	if err := opened.Unlock("vault secret"); err != nil {
+		t.Fatalf("Unlock with the vault passphrase failed: %v", err)
+	}
+	if mnemonic, _ := opened.GetMnemonic(); mnemonic != demoMnemonic {
+		t.Errorf("GetMnemonic = %q after Unlock", mnemonic)
+	}
+}
+
+// findAccount returns the account of w with the given address
+func findAccount(w *SimpleWallet, address Address) *Account {
+	for _, account := range w.Accounts() {
+		if account.Address == address {
+			return account
+		}
+	}
+	return nil
+}
+
+func TestVaultSeedWallet(t *testing.T) {
+	useFastVaultKDF(t)
+	path := filepath.Join(t.TempDir(), "seed.json")
+
+	seed := generateSeedFromMnemonic(demoMnemonic, "")
+	wallet, err := NewFromSeed(seed, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+	account, _ := wallet.Derive(3)
+	if err := wallet.Save(path, ""); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	opened, err := OpenWallet(path, "", nil)
+	if err != nil {
+		t.Fatalf("OpenWallet failed: %v", err)
+	}
+	defer opened.Close()
+	if mnemonic, _ := opened.GetMnemonic(); mnemonic != "" {
+		t.Errorf("Seed wallet opened with mnemonic %q", mnemonic)
+	}
+	if findAccount(opened, account.Address) == nil {
+		t.Error("Opened seed wallet lacks its account")
+	}
+}
+
+func TestVaultErrors(t *testing.T) {
+	useFastVaultKDF(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wallet.json")
+
+	wallet, err := NewFromMnemonic(demoMnemonic, nil)
+	if err != nil {
+		t.Fatalf("Failed to create wallet: %v", err)
+	}
+	defer wallet.Close()
+	wallet.SetLabel("original")
+	if err := wallet.Save(path, "secret"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	tests := []struct {
+		name string
+		path string
+		want error
+	}{
+		{"Not JSON", write("garbage.json", "{"), ErrInvalidVault},
+		{"Future version", write("v2.json", strings.Replace(string(data), `"version": 1`, `"version": 2`, 1)), ErrInvalidVault},
+		{"Unknown cipher", write("cipher.json", strings.Replace(string(data), `"aes-256-gcm"`, `"aes-256-cbc"`, 1)), ErrInvalidVault},
+		// The header is authenticated, so relabelling a vault breaks it
+		{"Modified header", write("label.json", strings.Replace(string(data), `"original"`, `"forged"`, 1)), ErrInvalidPassphrase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := OpenWallet(tt.path, "secret", nil); !errors.Is(err, tt.want) {
+				t.Errorf("OpenWallet error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := OpenWallet(filepath.Join(dir, "missing.json"), "secret", nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenWallet error = %v, want os.ErrNotExist", err)
+	}
+
+	// SaveNew never replaces a file
+	if err := wallet.SaveNew(path, "other"); !errors.Is(err, os.ErrExist) {
+		t.Errorf("SaveNew error = %v, want os.ErrExist", err)
+	}
+	if current, _ := os.ReadFile(path); string(current) != string(data) {
+		t.Error("SaveNew modified the existing vault")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 5 {
+		t.Errorf("Directory has %d entries, want no temporary files left", len(entries))
+	}
+}